@@ -0,0 +1,392 @@
+package ast
+
+// ApplyFunc is called by Apply for every node it visits. Returning the node unchanged keeps it, returning a
+// different node of the same kind replaces it, and returning nil deletes it from its parent. When skipChildren
+// is true Apply doesn't descend into the returned node.
+type ApplyFunc func(node interface{}) (replacement interface{}, skipChildren bool)
+
+// Apply visits node and everything below it in document order, parents before their children, and rebuilds the
+// lists and fields of each parent from the nodes returned by fn. It returns the replacement of node itself, or
+// nil if it was deleted.
+//
+// Apply understands query and schema documents and every node within them: definitions, fields, selections,
+// arguments, directives, values and types. Values are visited directly, without their ChildValue wrapper, so
+// deleting an object field's value deletes the field, and likewise deleting an argument's value deletes the
+// argument. References filled in by validation, such as
+// Field.Definition, aren't followed and may be stale once the document is changed.
+//
+// A replacement has to fit where the node was found, eg any Selection can replace a *Field in a selection set,
+// but a *Field can't replace a *Directive. Apply panics otherwise.
+func Apply(node interface{}, fn ApplyFunc) interface{} {
+	a := applier{fn: fn}
+	return a.apply(node)
+}
+
+type applier struct {
+	fn ApplyFunc
+}
+
+func (a *applier) apply(node interface{}) interface{} {
+	replacement, skipChildren := a.fn(node)
+	if replacement == nil || skipChildren {
+		return replacement
+	}
+
+	switch n := replacement.(type) {
+	case *QueryDocument:
+		n.Operations = a.operations(n.Operations)
+		n.Fragments = a.fragments(n.Fragments)
+	case *OperationDefinition:
+		n.VariableDefinitions = a.variableDefinitions(n.VariableDefinitions)
+		n.Directives = a.directives(n.Directives)
+		n.SelectionSet = a.selectionSet(n.SelectionSet)
+	case *VariableDefinition:
+		n.Type = a.typ(n.Type)
+		n.DefaultValue = a.value(n.DefaultValue)
+		n.Directives = a.directives(n.Directives)
+	case *FragmentDefinition:
+		n.VariableDefinition = a.variableDefinitions(n.VariableDefinition)
+		n.Directives = a.directives(n.Directives)
+		n.SelectionSet = a.selectionSet(n.SelectionSet)
+	case *Field:
+		n.Arguments = a.arguments(n.Arguments)
+		n.Directives = a.directives(n.Directives)
+		n.SelectionSet = a.selectionSet(n.SelectionSet)
+	case *FragmentSpread:
+		n.Directives = a.directives(n.Directives)
+	case *InlineFragment:
+		n.Directives = a.directives(n.Directives)
+		n.SelectionSet = a.selectionSet(n.SelectionSet)
+	case *Argument:
+		if n.Value = a.value(n.Value); n.Value == nil {
+			return nil
+		}
+	case *Directive:
+		n.Arguments = a.arguments(n.Arguments)
+	case *Value:
+		n.Children = a.childValues(n.Children)
+	case *Type:
+		n.Elem = a.typ(n.Elem)
+
+	case *SchemaDocument:
+		n.Schema = a.schemaDefinitions(n.Schema)
+		n.SchemaExtension = a.schemaDefinitions(n.SchemaExtension)
+		n.Directives = a.directiveDefinitions(n.Directives)
+		n.Definitions = a.definitions(n.Definitions)
+		n.Extensions = a.definitions(n.Extensions)
+	case *SchemaDefinition:
+		n.Directives = a.directives(n.Directives)
+		n.OperationTypes = a.operationTypes(n.OperationTypes)
+	case *DirectiveDefinition:
+		n.Arguments = a.argumentDefinitions(n.Arguments)
+	case *Definition:
+		n.Directives = a.directives(n.Directives)
+		n.Fields = a.fieldDefinitions(n.Fields)
+		n.EnumValues = a.enumValues(n.EnumValues)
+	case *FieldDefinition:
+		n.Arguments = a.argumentDefinitions(n.Arguments)
+		n.DefaultValue = a.value(n.DefaultValue)
+		n.Type = a.typ(n.Type)
+		n.Directives = a.directives(n.Directives)
+	case *ArgumentDefinition:
+		n.DefaultValue = a.value(n.DefaultValue)
+		n.Type = a.typ(n.Type)
+		n.Directives = a.directives(n.Directives)
+	case *EnumValueDefinition:
+		n.Directives = a.directives(n.Directives)
+	}
+
+	return replacement
+}
+
+func (a *applier) typ(t *Type) *Type {
+	if t == nil {
+		return nil
+	}
+	r := a.apply(t)
+	if r == nil {
+		return nil
+	}
+	return r.(*Type)
+}
+
+func (a *applier) value(v *Value) *Value {
+	if v == nil {
+		return nil
+	}
+	r := a.apply(v)
+	if r == nil {
+		return nil
+	}
+	return r.(*Value)
+}
+
+func (a *applier) operations(list OperationList) OperationList {
+	if len(list) == 0 {
+		return list
+	}
+	var out OperationList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*OperationDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) fragments(list FragmentDefinitionList) FragmentDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out FragmentDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*FragmentDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) variableDefinitions(list VariableDefinitionList) VariableDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out VariableDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*VariableDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) selectionSet(list SelectionSet) SelectionSet {
+	if len(list) == 0 {
+		return list
+	}
+	var out SelectionSet
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(Selection))
+		}
+	}
+	return out
+}
+
+func (a *applier) arguments(list ArgumentList) ArgumentList {
+	if len(list) == 0 {
+		return list
+	}
+	var out ArgumentList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*Argument))
+		}
+	}
+	return out
+}
+
+func (a *applier) directives(list DirectiveList) DirectiveList {
+	if len(list) == 0 {
+		return list
+	}
+	var out DirectiveList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*Directive))
+		}
+	}
+	return out
+}
+
+func (a *applier) childValues(list ChildValueList) ChildValueList {
+	if len(list) == 0 {
+		return list
+	}
+	var out ChildValueList
+	for _, it := range list {
+		if it.Value = a.value(it.Value); it.Value != nil {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func (a *applier) schemaDefinitions(list SchemaDefinitionList) SchemaDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out SchemaDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*SchemaDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) operationTypes(list OperationTypeDefinitionList) OperationTypeDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out OperationTypeDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*OperationTypeDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) directiveDefinitions(list DirectiveDefinitionList) DirectiveDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out DirectiveDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*DirectiveDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) definitions(list DefinitionList) DefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out DefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*Definition))
+		}
+	}
+	return out
+}
+
+func (a *applier) fieldDefinitions(list FieldList) FieldList {
+	if len(list) == 0 {
+		return list
+	}
+	var out FieldList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*FieldDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) argumentDefinitions(list ArgumentDefinitionList) ArgumentDefinitionList {
+	if len(list) == 0 {
+		return list
+	}
+	var out ArgumentDefinitionList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*ArgumentDefinition))
+		}
+	}
+	return out
+}
+
+func (a *applier) enumValues(list EnumValueList) EnumValueList {
+	if len(list) == 0 {
+		return list
+	}
+	var out EnumValueList
+	for _, it := range list {
+		if r := a.apply(it); r != nil {
+			out = append(out, r.(*EnumValueDefinition))
+		}
+	}
+	return out
+}
+
+// RemoveField returns the selection set without the fields with the given response key, the alias if there is
+// one or else the name. Fields in inline fragments of the set are removed too, as they share its response object,
+// but fragment spreads are left alone since the fragment might be spread elsewhere.
+func RemoveField(set SelectionSet, responseKey string) SelectionSet {
+	if len(set) == 0 {
+		return set
+	}
+	var out SelectionSet
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			if sel.Alias == responseKey || (sel.Alias == "" && sel.Name == responseKey) {
+				continue
+			}
+		case *InlineFragment:
+			sel.SelectionSet = RemoveField(sel.SelectionSet, responseKey)
+		}
+		out = append(out, sel)
+	}
+	return out
+}
+
+// RenameType renames the type called from to to everywhere in node: its definition and extensions, every type
+// reference, implemented interface and union member, fragment type conditions and root operation types. node can
+// be a document, a node within one, or a loaded *Schema, whose Types, PossibleTypes and Implements are then keyed
+// by the new name.
+func RenameType(node interface{}, from, to string) {
+	rename := func(names []string) {
+		for i, name := range names {
+			if name == from {
+				names[i] = to
+			}
+		}
+	}
+
+	fn := func(node interface{}) (interface{}, bool) {
+		switch n := node.(type) {
+		case *Definition:
+			if n.Name == from {
+				n.Name = to
+			}
+			rename(n.Interfaces)
+			rename(n.Types)
+		case *Type:
+			if n.NamedType == from {
+				n.NamedType = to
+			}
+		case *FragmentDefinition:
+			if n.TypeCondition == from {
+				n.TypeCondition = to
+			}
+		case *InlineFragment:
+			if n.TypeCondition == from {
+				n.TypeCondition = to
+			}
+		case *OperationTypeDefinition:
+			if n.Type == from {
+				n.Type = to
+			}
+		}
+		return node, false
+	}
+
+	schema, ok := node.(*Schema)
+	if !ok {
+		Apply(node, fn)
+		return
+	}
+	for _, def := range schema.Types {
+		Apply(def, fn)
+	}
+	for _, dir := range schema.Directives {
+		Apply(dir, fn)
+	}
+	if def, ok := schema.Types[from]; ok {
+		delete(schema.Types, from)
+		schema.Types[to] = def
+	}
+	if defs, ok := schema.PossibleTypes[from]; ok {
+		delete(schema.PossibleTypes, from)
+		schema.PossibleTypes[to] = defs
+	}
+	if defs, ok := schema.Implements[from]; ok {
+		delete(schema.Implements, from)
+		schema.Implements[to] = defs
+	}
+}
@@ -0,0 +1,178 @@
+package ast_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/formatter"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestApply(t *testing.T) {
+	formatSchema := func(doc *SchemaDocument) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf).FormatSchemaDocument(doc)
+		return buf.String()
+	}
+	formatQuery := func(doc *QueryDocument) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+		return buf.String()
+	}
+
+	t.Run("delete deprecated fields", func(t *testing.T) {
+		doc := parser.MustParseSchema(&Source{Input: `
+			type User {
+				id: ID!
+				name: String @deprecated
+				email: String
+			}
+			enum Role { ADMIN GUEST @deprecated }
+			extend type User { nick: String @deprecated(reason: "use name") }
+		`})
+
+		Apply(doc, func(node interface{}) (interface{}, bool) {
+			switch n := node.(type) {
+			case *FieldDefinition:
+				if n.Directives.ForName("deprecated") != nil {
+					return nil, false
+				}
+			case *EnumValueDefinition:
+				if n.Directives.ForName("deprecated") != nil {
+					return nil, false
+				}
+			}
+			return node, false
+		})
+
+		require.Equal(t, "type User {\n\tid: ID!\n\temail: String\n}\nenum Role {\n\tADMIN\n}\nextend type User\n", formatSchema(doc))
+	})
+
+	t.Run("replace and skip", func(t *testing.T) {
+		doc := parser.MustParseQuery(&Source{Input: `{ user(id: 1) { name friends { name } } ...F } fragment F on Query { me { name } }`})
+
+		var visited []string
+		Apply(doc, func(node interface{}) (interface{}, bool) {
+			field, ok := node.(*Field)
+			if !ok {
+				return node, false
+			}
+			visited = append(visited, field.Name)
+			switch field.Name {
+			case "friends":
+				return node, true
+			case "me":
+				return &InlineFragment{TypeCondition: "Query", SelectionSet: SelectionSet{&Field{Alias: "me", Name: "viewer"}}}, false
+			}
+			return node, false
+		})
+
+		require.Equal(t, []string{"user", "name", "friends", "me", "viewer"}, visited)
+		require.Equal(t, "query {\n\tuser(id: 1) {\n\t\tname\n\t\tfriends {\n\t\t\tname\n\t\t}\n\t}\n\t... F\n}\n"+
+			"fragment F on Query {\n\t... on Query {\n\t\tme: viewer\n\t}\n}\n", formatQuery(doc))
+	})
+
+	t.Run("values and types", func(t *testing.T) {
+		doc := parser.MustParseQuery(&Source{Input: `query ($ids: [ID!]) { users(ids: $ids, filter: {name: "a", secret: "b"}) { name } }`})
+
+		Apply(doc, func(node interface{}) (interface{}, bool) {
+			switch n := node.(type) {
+			case *Value:
+				if n.Raw == "b" {
+					return nil, false
+				}
+			case *Type:
+				if n.NamedType == "ID" {
+					return &Type{NamedType: "String", NonNull: n.NonNull}, false
+				}
+			}
+			return node, false
+		})
+
+		require.Equal(t, "query ($ids: [String!]) {\n\tusers(ids: $ids, filter: {name:\"a\"}) {\n\t\tname\n\t}\n}\n", formatQuery(doc))
+	})
+
+	t.Run("deleting an argument's value deletes the argument", func(t *testing.T) {
+		doc := parser.MustParseQuery(&Source{Input: `{ users(first: 10, after: null) @include(if: null) { name } }`})
+
+		Apply(doc, func(node interface{}) (interface{}, bool) {
+			if v, ok := node.(*Value); ok && v.Kind == NullValue {
+				return nil, false
+			}
+			return node, false
+		})
+
+		require.Equal(t, "query {\n\tusers(first: 10) @include {\n\t\tname\n\t}\n}\n", formatQuery(doc))
+	})
+
+	t.Run("delete the root", func(t *testing.T) {
+		doc := parser.MustParseQuery(&Source{Input: `{ name }`})
+		require.Nil(t, Apply(doc, func(node interface{}) (interface{}, bool) { return nil, false }))
+	})
+}
+
+func TestRemoveField(t *testing.T) {
+	doc := parser.MustParseQuery(&Source{Input: `{ name id ... on Query { name other: name } ...F alias: id }`})
+
+	set := RemoveField(doc.Operations[0].SelectionSet, "name")
+	require.Len(t, set, 4)
+	require.Equal(t, "id", set[0].(*Field).Alias)
+	require.Equal(t, "other", set[1].(*InlineFragment).SelectionSet[0].(*Field).Alias)
+	require.Len(t, set[1].(*InlineFragment).SelectionSet, 1)
+	require.Equal(t, "F", set[2].(*FragmentSpread).Name)
+
+	set = RemoveField(set, "alias")
+	require.Len(t, set, 3)
+}
+
+func TestRenameType(t *testing.T) {
+	schema := parser.MustParseSchema(&Source{Input: `
+		schema { query: User }
+		interface Node { id: ID! }
+		type User implements Node { id: ID! friends(first: Int, of: User): [User!]! }
+		union Result = User | Node
+		extend type User { best: User }
+	`})
+	RenameType(schema, "User", "Person")
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchemaDocument(schema)
+	require.Equal(t, `schema {
+	query: Person
+}
+interface Node {
+	id: ID!
+}
+type Person implements Node {
+	id: ID!
+	friends(first: Int, of: Person): [Person!]!
+}
+union Result = Person | Node
+extend type Person {
+	best: Person
+}
+`, buf.String())
+
+	query := parser.MustParseQuery(&Source{Input: `query ($u: User) { ... on User { id } ...F } fragment F on User { id }`})
+	RenameType(query, "User", "Person")
+	require.Equal(t, "Person", query.Operations[0].VariableDefinitions[0].Type.NamedType)
+	require.Equal(t, "Person", query.Operations[0].SelectionSet[0].(*InlineFragment).TypeCondition)
+	require.Equal(t, "Person", query.Fragments[0].TypeCondition)
+
+	loaded, err := validator.LoadSchema(validator.Prelude, &Source{Input: `
+		directive @link(to: User) on FIELD_DEFINITION
+		input User { id: ID }
+		type Query { user(u: User): ID @link }
+	`})
+	require.Nil(t, err)
+	RenameType(loaded, "User", "Person")
+	require.Nil(t, loaded.Types["User"])
+	require.Equal(t, "Person", loaded.Types["Person"].Name)
+	require.Equal(t, "Person", loaded.Query.Fields.ForName("user").Arguments[0].Type.NamedType)
+	require.Equal(t, "Person", loaded.Directives["link"].Arguments[0].Type.NamedType)
+	require.Equal(t, "Person", loaded.PossibleTypes["Person"][0].Name)
+}
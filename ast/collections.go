@@ -33,6 +33,8 @@ func (l DirectiveList) ForName(name string) *Directive {
 	return nil
 }
 
+// ForNames returns every directive with the given name in source order, eg for directives that may be
+// applied more than once to the same node. ForName only returns the first.
 func (l DirectiveList) ForNames(name string) []*Directive {
 	resp := []*Directive{}
 	for _, it := range l {
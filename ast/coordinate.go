@@ -0,0 +1,135 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaCoordinate identifies a single element of a schema, eg `User.email`, `Query.user(id:)` or `@deprecated(reason:)`.
+// See https://spec.graphql.org/draft/#sec-Schema-Coordinates
+type SchemaCoordinate struct {
+	Type      string // set for type, field, input field, enum value and argument coordinates
+	Directive string // set for directive and directive argument coordinates
+	Member    string // field, input field or enum value name
+	Argument  string
+}
+
+// ParseSchemaCoordinate parses the string form of a schema coordinate.
+func ParseSchemaCoordinate(coord string) (SchemaCoordinate, error) {
+	var c SchemaCoordinate
+	rest := coord
+
+	if strings.HasSuffix(rest, ":)") {
+		open := strings.IndexByte(rest, '(')
+		if open < 0 {
+			return c, fmt.Errorf("invalid schema coordinate %q", coord)
+		}
+		c.Argument = rest[open+1 : len(rest)-2]
+		rest = rest[:open]
+		if !isName(c.Argument) {
+			return c, fmt.Errorf("invalid schema coordinate %q", coord)
+		}
+	}
+
+	if strings.HasPrefix(rest, "@") {
+		c.Directive = rest[1:]
+		if !isName(c.Directive) {
+			return c, fmt.Errorf("invalid schema coordinate %q", coord)
+		}
+		return c, nil
+	}
+
+	if dot := strings.IndexByte(rest, '.'); dot >= 0 {
+		c.Type, c.Member = rest[:dot], rest[dot+1:]
+		if !isName(c.Member) {
+			return c, fmt.Errorf("invalid schema coordinate %q", coord)
+		}
+	} else {
+		c.Type = rest
+		if c.Argument != "" {
+			return c, fmt.Errorf("invalid schema coordinate %q", coord)
+		}
+	}
+
+	if !isName(c.Type) {
+		return c, fmt.Errorf("invalid schema coordinate %q", coord)
+	}
+	return c, nil
+}
+
+func (c SchemaCoordinate) String() string {
+	var s string
+	if c.Directive != "" {
+		s = "@" + c.Directive
+	} else {
+		s = c.Type
+		if c.Member != "" {
+			s += "." + c.Member
+		}
+	}
+	if c.Argument != "" {
+		s += "(" + c.Argument + ":)"
+	}
+	return s
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// DescriptionFor returns the description of the element the coordinate refers to, and false if
+// it doesn't exist in the schema.
+func (s *Schema) DescriptionFor(coord SchemaCoordinate) (string, bool) {
+	if coord.Directive != "" {
+		dir := s.Directives[coord.Directive]
+		if dir == nil {
+			return "", false
+		}
+		if coord.Argument == "" {
+			return dir.Description, true
+		}
+		arg := dir.Arguments.ForName(coord.Argument)
+		if arg == nil {
+			return "", false
+		}
+		return arg.Description, true
+	}
+
+	def := s.Types[coord.Type]
+	if def == nil {
+		return "", false
+	}
+	if coord.Member == "" {
+		return def.Description, true
+	}
+
+	if def.Kind == Enum {
+		value := def.EnumValues.ForName(coord.Member)
+		if value == nil || coord.Argument != "" {
+			return "", false
+		}
+		return value.Description, true
+	}
+
+	field := def.Fields.ForName(coord.Member)
+	if field == nil {
+		return "", false
+	}
+	if coord.Argument == "" {
+		return field.Description, true
+	}
+	arg := field.Arguments.ForName(coord.Argument)
+	if arg == nil {
+		return "", false
+	}
+	return arg.Description, true
+}
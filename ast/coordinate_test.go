@@ -0,0 +1,98 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestParseSchemaCoordinate(t *testing.T) {
+	for coord, expected := range map[string]SchemaCoordinate{
+		"User":                 {Type: "User"},
+		"User.email":           {Type: "User", Member: "email"},
+		"Query.user(id:)":      {Type: "Query", Member: "user", Argument: "id"},
+		"@deprecated":          {Directive: "deprecated"},
+		"@deprecated(reason:)": {Directive: "deprecated", Argument: "reason"},
+	} {
+		t.Run(coord, func(t *testing.T) {
+			c, err := ParseSchemaCoordinate(coord)
+			require.NoError(t, err)
+			require.Equal(t, expected, c)
+			require.Equal(t, coord, c.String())
+		})
+	}
+
+	for _, coord := range []string{"", "User.", ".email", "User(id:)", "@", "Query.user(:)", "Query.user(id)", "1User"} {
+		t.Run(coord, func(t *testing.T) {
+			_, err := ParseSchemaCoordinate(coord)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSchemaDescriptionFor(t *testing.T) {
+	s, gqlErr := validator.LoadSchema(validator.Prelude, &Source{Input: `
+		"A user"
+		type User {
+			"The user's email"
+			email: String
+		}
+		type Query {
+			user(
+				"The user's id"
+				id: ID!
+			): User
+		}
+		enum Role {
+			"Can do anything"
+			ADMIN
+		}
+	`})
+	require.Nil(t, gqlErr)
+
+	description := func(coord string) (string, bool) {
+		c, err := ParseSchemaCoordinate(coord)
+		require.NoError(t, err)
+		return s.DescriptionFor(c)
+	}
+
+	t.Run("type", func(t *testing.T) {
+		desc, ok := description("User")
+		require.True(t, ok)
+		require.Equal(t, "A user", desc)
+	})
+
+	t.Run("field", func(t *testing.T) {
+		desc, ok := description("User.email")
+		require.True(t, ok)
+		require.Equal(t, "The user's email", desc)
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		desc, ok := description("Query.user(id:)")
+		require.True(t, ok)
+		require.Equal(t, "The user's id", desc)
+	})
+
+	t.Run("enum value", func(t *testing.T) {
+		desc, ok := description("Role.ADMIN")
+		require.True(t, ok)
+		require.Equal(t, "Can do anything", desc)
+	})
+
+	t.Run("directive", func(t *testing.T) {
+		_, ok := description("@deprecated(reason:)")
+		require.True(t, ok)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		for _, coord := range []string{"Account", "User.name", "Query.user(name:)", "@unknown"} {
+			desc, ok := description(coord)
+			require.False(t, ok, coord)
+			require.Equal(t, "", desc)
+		}
+	})
+}
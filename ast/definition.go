@@ -31,6 +31,9 @@ type Definition struct {
 
 	Position *Position `dump:"-"`
 	BuiltIn  bool      `dump:"-"`
+	// LeadingBlankLine is set by the parser when the definition was separated from the one before it by an
+	// empty line, see formatter.WithBlankLines.
+	LeadingBlankLine bool `dump:"-"`
 }
 
 func (d *Definition) IsLeafType() bool {
@@ -66,6 +69,14 @@ type FieldDefinition struct {
 	Type         *Type
 	Directives   DirectiveList
 	Position     *Position `dump:"-"`
+	// LeadingBlankLine is set when the field was separated from the one before it by an empty line.
+	LeadingBlankLine bool `dump:"-"`
+}
+
+// IsDeprecated reports whether the field is marked with the built-in @deprecated directive, and the
+// deprecation reason.
+func (f *FieldDefinition) IsDeprecated() (bool, string) {
+	return deprecation(f.Directives)
 }
 
 type ArgumentDefinition struct {
@@ -77,17 +88,54 @@ type ArgumentDefinition struct {
 	Position     *Position `dump:"-"`
 }
 
+// IsDeprecated reports whether the argument is marked with the built-in @deprecated directive, and the
+// deprecation reason.
+func (a *ArgumentDefinition) IsDeprecated() (bool, string) {
+	return deprecation(a.Directives)
+}
+
 type EnumValueDefinition struct {
 	Description string
 	Name        string
 	Directives  DirectiveList
 	Position    *Position `dump:"-"`
+	// LeadingBlankLine is set when the value was separated from the one before it by an empty line.
+	LeadingBlankLine bool `dump:"-"`
+}
+
+// IsDeprecated reports whether the enum value is marked with the built-in @deprecated directive, and
+// the deprecation reason.
+func (v *EnumValueDefinition) IsDeprecated() (bool, string) {
+	return deprecation(v.Directives)
+}
+
+const defaultDeprecationReason = "No longer supported"
+
+func deprecation(directives DirectiveList) (bool, string) {
+	for _, d := range directives {
+		if d.Name != "deprecated" {
+			continue
+		}
+		// once validated, a directive bound to a user definition is not the built-in one
+		if d.Definition != nil && !d.Definition.BuiltIn {
+			continue
+		}
+		if reason := d.Arguments.ForName("reason"); reason != nil && reason.Value != nil && reason.Value.Kind == StringValue {
+			return true, reason.Value.Raw
+		}
+		return true, defaultDeprecationReason
+	}
+	return false, ""
 }
 
 type DirectiveDefinition struct {
-	Description string
-	Name        string
-	Arguments   ArgumentDefinitionList
-	Locations   []DirectiveLocation
-	Position    *Position `dump:"-"`
+	Description  string
+	Name         string
+	Arguments    ArgumentDefinitionList
+	IsRepeatable bool
+	Locations    []DirectiveLocation
+	Position     *Position `dump:"-"`
+	BuiltIn      bool      `dump:"-"`
+	// LeadingBlankLine is set when the definition was separated from the one before it by an empty line.
+	LeadingBlankLine bool `dump:"-"`
 }
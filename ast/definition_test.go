@@ -0,0 +1,76 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestIsDeprecated(t *testing.T) {
+	schema, err := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: `
+		type Query {
+			current: String
+			old: String @deprecated
+			older: String @deprecated(reason: "Use current")
+		}
+		enum Color {
+			RED
+			ROUGE @deprecated(reason: "Use RED")
+			ROT @deprecated
+		}
+		input Filter {
+			name: String
+			nom: String @deprecated(reason: "Use name")
+		}
+		type Mutation {
+			paint(color: Color, colour: Color @deprecated): Boolean
+		}
+	`})
+	require.Nil(t, err)
+
+	query := schema.Types["Query"]
+	for name, expected := range map[string]string{"current": "", "old": "No longer supported", "older": "Use current"} {
+		deprecated, reason := query.Fields.ForName(name).IsDeprecated()
+		require.Equal(t, expected != "", deprecated, name)
+		require.Equal(t, expected, reason, name)
+	}
+
+	color := schema.Types["Color"]
+	for name, expected := range map[string]string{"RED": "", "ROUGE": "Use RED", "ROT": "No longer supported"} {
+		deprecated, reason := color.EnumValues.ForName(name).IsDeprecated()
+		require.Equal(t, expected != "", deprecated, name)
+		require.Equal(t, expected, reason, name)
+	}
+
+	filter := schema.Types["Filter"]
+	for name, expected := range map[string]string{"name": "", "nom": "Use name"} {
+		deprecated, reason := filter.Fields.ForName(name).IsDeprecated()
+		require.Equal(t, expected != "", deprecated, name)
+		require.Equal(t, expected, reason, name)
+	}
+
+	paint := schema.Types["Mutation"].Fields.ForName("paint")
+	for name, expected := range map[string]string{"color": "", "colour": "No longer supported"} {
+		deprecated, reason := paint.Arguments.ForName(name).IsDeprecated()
+		require.Equal(t, expected != "", deprecated, name)
+		require.Equal(t, expected, reason, name)
+	}
+
+	t.Run("user defined directive", func(t *testing.T) {
+		schema, err := validator.LoadSchema(&Source{Name: "schema", Input: `
+			scalar Text
+			directive @deprecated(reason: Text) on FIELD_DEFINITION
+			type Query {
+				old: Text @deprecated(reason: "not really")
+			}
+		`})
+		require.Nil(t, err)
+
+		deprecated, reason := schema.Types["Query"].Fields.ForName("old").IsDeprecated()
+		require.False(t, deprecated)
+		require.Equal(t, "", reason)
+	})
+}
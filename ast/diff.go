@@ -0,0 +1,301 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+)
+
+type ChangeCategory string
+
+const (
+	// ChangeBreaking will break existing clients, eg a removed field or a newly required argument.
+	ChangeBreaking ChangeCategory = "BREAKING"
+	// ChangeDangerous is compatible at the schema level but may change runtime behaviour, eg a changed default value.
+	ChangeDangerous ChangeCategory = "DANGEROUS"
+	// ChangeSafe is fully backwards compatible, eg a new type or field.
+	ChangeSafe ChangeCategory = "SAFE"
+)
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Category    ChangeCategory
+	Description string
+	Path        SchemaCoordinate
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s: %s", c.Category, c.Path.String(), c.Description)
+}
+
+// Diff compares two versions of a schema and reports every change to types, fields, arguments, enum values,
+// union members, implemented interfaces and directive definitions, classified by its impact on existing clients.
+// Changes are grouped by type in name order, followed by directive changes.
+func Diff(oldSchema, newSchema *Schema) []Change {
+	d := differ{}
+
+	for _, name := range unionKeys(oldSchema.Types, newSchema.Types) {
+		oldDef, newDef := oldSchema.Types[name], newSchema.Types[name]
+		switch {
+		case newDef == nil:
+			d.add(ChangeBreaking, SchemaCoordinate{Type: name}, "type %s was removed", name)
+		case oldDef == nil:
+			d.add(ChangeSafe, SchemaCoordinate{Type: name}, "type %s was added", name)
+		case oldDef.Kind != newDef.Kind:
+			d.add(ChangeBreaking, SchemaCoordinate{Type: name}, "type %s changed from %s to %s", name, oldDef.Kind, newDef.Kind)
+		default:
+			d.diffDefinition(oldDef, newDef)
+		}
+	}
+
+	for _, name := range unionDirectiveKeys(oldSchema.Directives, newSchema.Directives) {
+		oldDir, newDir := oldSchema.Directives[name], newSchema.Directives[name]
+		coord := SchemaCoordinate{Directive: name}
+		switch {
+		case newDir == nil:
+			d.add(ChangeBreaking, coord, "directive @%s was removed", name)
+		case oldDir == nil:
+			d.add(ChangeSafe, coord, "directive @%s was added", name)
+		default:
+			d.diffArguments(coord, oldDir.Arguments, newDir.Arguments)
+			for _, loc := range oldDir.Locations {
+				if !hasLocation(newDir.Locations, loc) {
+					d.add(ChangeBreaking, coord, "location %s was removed from directive @%s", loc, name)
+				}
+			}
+			for _, loc := range newDir.Locations {
+				if !hasLocation(oldDir.Locations, loc) {
+					d.add(ChangeSafe, coord, "location %s was added to directive @%s", loc, name)
+				}
+			}
+		}
+	}
+
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(category ChangeCategory, path SchemaCoordinate, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Category:    category,
+		Description: fmt.Sprintf(format, args...),
+		Path:        path,
+	})
+}
+
+func (d *differ) diffDefinition(oldDef, newDef *Definition) {
+	typeCoord := SchemaCoordinate{Type: newDef.Name}
+
+	switch newDef.Kind {
+	case Object, Interface:
+		d.diffFields(oldDef, newDef)
+		for _, name := range oldDef.Interfaces {
+			if !hasName(newDef.Interfaces, name) {
+				d.add(ChangeBreaking, typeCoord, "%s no longer implements interface %s", newDef.Name, name)
+			}
+		}
+		for _, name := range newDef.Interfaces {
+			if !hasName(oldDef.Interfaces, name) {
+				d.add(ChangeDangerous, typeCoord, "%s now implements interface %s", newDef.Name, name)
+			}
+		}
+
+	case InputObject:
+		d.diffInputFields(oldDef, newDef)
+
+	case Union:
+		for _, name := range oldDef.Types {
+			if !hasName(newDef.Types, name) {
+				d.add(ChangeBreaking, typeCoord, "%s was removed from union %s", name, newDef.Name)
+			}
+		}
+		for _, name := range newDef.Types {
+			if !hasName(oldDef.Types, name) {
+				d.add(ChangeDangerous, typeCoord, "%s was added to union %s", name, newDef.Name)
+			}
+		}
+
+	case Enum:
+		for _, value := range oldDef.EnumValues {
+			if newDef.EnumValues.ForName(value.Name) == nil {
+				d.add(ChangeBreaking, SchemaCoordinate{Type: newDef.Name, Member: value.Name}, "enum value %s was removed from %s", value.Name, newDef.Name)
+			}
+		}
+		for _, value := range newDef.EnumValues {
+			if oldDef.EnumValues.ForName(value.Name) == nil {
+				d.add(ChangeDangerous, SchemaCoordinate{Type: newDef.Name, Member: value.Name}, "enum value %s was added to %s", value.Name, newDef.Name)
+			}
+		}
+	}
+}
+
+func (d *differ) diffFields(oldDef, newDef *Definition) {
+	for _, oldField := range oldDef.Fields {
+		coord := SchemaCoordinate{Type: newDef.Name, Member: oldField.Name}
+		newField := newDef.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(ChangeBreaking, coord, "field %s was removed", coord.String())
+			continue
+		}
+
+		if !oldField.Type.Equals(newField.Type) {
+			category := ChangeBreaking
+			if isSafeOutputChange(oldField.Type, newField.Type) {
+				category = ChangeSafe
+			}
+			d.add(category, coord, "field %s changed type from %s to %s", coord.String(), oldField.Type.String(), newField.Type.String())
+		}
+
+		d.diffArguments(coord, oldField.Arguments, newField.Arguments)
+	}
+
+	for _, newField := range newDef.Fields {
+		if oldDef.Fields.ForName(newField.Name) == nil {
+			coord := SchemaCoordinate{Type: newDef.Name, Member: newField.Name}
+			d.add(ChangeSafe, coord, "field %s was added", coord.String())
+		}
+	}
+}
+
+func (d *differ) diffInputFields(oldDef, newDef *Definition) {
+	for _, oldField := range oldDef.Fields {
+		coord := SchemaCoordinate{Type: newDef.Name, Member: oldField.Name}
+		newField := newDef.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(ChangeBreaking, coord, "input field %s was removed", coord.String())
+			continue
+		}
+		d.diffInputValue(coord, "input field", oldField.Type, newField.Type, oldField.DefaultValue, newField.DefaultValue)
+	}
+
+	for _, newField := range newDef.Fields {
+		if oldDef.Fields.ForName(newField.Name) != nil {
+			continue
+		}
+		coord := SchemaCoordinate{Type: newDef.Name, Member: newField.Name}
+		if newField.Type.NonNull && newField.DefaultValue == nil {
+			d.add(ChangeBreaking, coord, "required input field %s was added", coord.String())
+		} else {
+			d.add(ChangeDangerous, coord, "optional input field %s was added", coord.String())
+		}
+	}
+}
+
+// diffArguments compares the arguments of a field or directive, identified by parent.
+func (d *differ) diffArguments(parent SchemaCoordinate, oldArgs, newArgs ArgumentDefinitionList) {
+	for _, oldArg := range oldArgs {
+		coord := parent
+		coord.Argument = oldArg.Name
+		newArg := newArgs.ForName(oldArg.Name)
+		if newArg == nil {
+			d.add(ChangeBreaking, coord, "argument %s was removed", coord.String())
+			continue
+		}
+		d.diffInputValue(coord, "argument", oldArg.Type, newArg.Type, oldArg.DefaultValue, newArg.DefaultValue)
+	}
+
+	for _, newArg := range newArgs {
+		if oldArgs.ForName(newArg.Name) != nil {
+			continue
+		}
+		coord := parent
+		coord.Argument = newArg.Name
+		if newArg.Type.NonNull && newArg.DefaultValue == nil {
+			d.add(ChangeBreaking, coord, "required argument %s was added", coord.String())
+		} else {
+			d.add(ChangeDangerous, coord, "optional argument %s was added", coord.String())
+		}
+	}
+}
+
+func (d *differ) diffInputValue(coord SchemaCoordinate, what string, oldType, newType *Type, oldDefault, newDefault *Value) {
+	if !oldType.Equals(newType) {
+		category := ChangeBreaking
+		if isSafeInputChange(oldType, newType) {
+			category = ChangeSafe
+		}
+		d.add(category, coord, "%s %s changed type from %s to %s", what, coord.String(), oldType.String(), newType.String())
+	}
+
+	switch {
+	case oldDefault == nil && newDefault == nil:
+	case oldDefault == nil:
+		d.add(ChangeDangerous, coord, "%s %s now has default value %s", what, coord.String(), newDefault.String())
+	case newDefault == nil:
+		d.add(ChangeDangerous, coord, "%s %s no longer has default value %s", what, coord.String(), oldDefault.String())
+	case oldDefault.String() != newDefault.String():
+		d.add(ChangeDangerous, coord, "%s %s changed default value from %s to %s", what, coord.String(), oldDefault.String(), newDefault.String())
+	}
+}
+
+// isSafeOutputChange reports whether clients reading a value of oldType can also read newType, ie the change only
+// made the type stricter.
+func isSafeOutputChange(oldType, newType *Type) bool {
+	if oldType.NonNull && !newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil {
+		return newType.Elem != nil && isSafeOutputChange(oldType.Elem, newType.Elem)
+	}
+	return newType.Elem == nil && oldType.NamedType == newType.NamedType
+}
+
+// isSafeInputChange reports whether every value clients send as oldType is still accepted as newType, ie the
+// change only made the type more lenient.
+func isSafeInputChange(oldType, newType *Type) bool {
+	if !oldType.NonNull && newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil {
+		return newType.Elem != nil && isSafeInputChange(oldType.Elem, newType.Elem)
+	}
+	return newType.Elem == nil && oldType.NamedType == newType.NamedType
+}
+
+func unionKeys(a, b map[string]*Definition) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionDirectiveKeys(a, b map[string]*DirectiveDefinition) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLocation(locations []DirectiveLocation, loc DirectiveLocation) bool {
+	for _, l := range locations {
+		if l == loc {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestDiff(t *testing.T) {
+	load := func(input string) *Schema {
+		schema, err := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: input})
+		require.Nil(t, err)
+		return schema
+	}
+
+	oldSchema := load(`
+		type Query {
+			user(id: ID!, limit: Int = 10): User
+			users: [User]
+			legacy: String
+		}
+		type User {
+			id: ID!
+			name: String!
+			email: String
+		}
+		input Filter {
+			name: String
+			age: Int!
+		}
+		enum Role { ADMIN USER GUEST }
+		type Unused { id: ID }
+	`)
+	newSchema := load(`
+		type Query {
+			user(id: ID!, limit: Int = 20, active: Boolean!): User
+			users: [User!]
+			search(filter: Filter): [User]
+		}
+		type User {
+			id: ID!
+			name: String
+			email: String
+			avatar: String
+		}
+		input Filter {
+			name: String
+			age: Int
+			role: Role!
+		}
+		enum Role { ADMIN USER }
+	`)
+
+	var changes []string
+	for _, change := range Diff(oldSchema, newSchema) {
+		changes = append(changes, change.String())
+	}
+
+	require.Equal(t, []string{
+		"SAFE Filter.age: input field Filter.age changed type from Int! to Int",
+		"BREAKING Filter.role: required input field Filter.role was added",
+		"DANGEROUS Query.user(limit:): argument Query.user(limit:) changed default value from 10 to 20",
+		"BREAKING Query.user(active:): required argument Query.user(active:) was added",
+		"SAFE Query.users: field Query.users changed type from [User] to [User!]",
+		"BREAKING Query.legacy: field Query.legacy was removed",
+		"SAFE Query.search: field Query.search was added",
+		"BREAKING Role.GUEST: enum value GUEST was removed from Role",
+		"BREAKING Unused: type Unused was removed",
+		"BREAKING User.name: field User.name changed type from String! to String",
+		"SAFE User.avatar: field User.avatar was added",
+	}, changes)
+
+	require.Empty(t, Diff(oldSchema, oldSchema))
+}
@@ -1,11 +1,47 @@
 package ast
 
+import "fmt"
+
 type QueryDocument struct {
 	Operations OperationList
 	Fragments  FragmentDefinitionList
 	Position   *Position `dump:"-"`
 }
 
+// Operation returns the operation with the given name. An empty name selects the only operation of a document
+// with exactly one. It returns nil if there is no such operation, see GetOperation for why.
+func (d *QueryDocument) Operation(name string) *OperationDefinition {
+	return d.Operations.ForName(name)
+}
+
+// FirstOperation returns the first operation in the document, or nil if it only has fragments.
+func (d *QueryDocument) FirstOperation() *OperationDefinition {
+	if len(d.Operations) == 0 {
+		return nil
+	}
+	return d.Operations[0]
+}
+
+// GetOperation selects the operation to execute as the spec requires: by name, or if name is empty the only
+// operation in the document.
+func (d *QueryDocument) GetOperation(name string) (*OperationDefinition, error) {
+	if name == "" {
+		switch len(d.Operations) {
+		case 0:
+			return nil, fmt.Errorf("document does not contain any operations")
+		case 1:
+			return d.Operations[0], nil
+		default:
+			return nil, fmt.Errorf("must provide operation name if query contains multiple operations")
+		}
+	}
+
+	if op := d.Operations.ForName(name); op != nil {
+		return op, nil
+	}
+	return nil, fmt.Errorf("unknown operation named %q", name)
+}
+
 type SchemaDocument struct {
 	Schema          SchemaDefinitionList
 	SchemaExtension SchemaDefinitionList
@@ -23,7 +59,12 @@ func (d *SchemaDocument) Merge(other *SchemaDocument) {
 	d.Extensions = append(d.Extensions, other.Extensions...)
 }
 
+// Schema is a loaded and validated schema. Once loaded it is only read, by validation and every lookup method, so
+// it is safe to share between goroutines as long as nothing changes it; see Freeze.
 type Schema struct {
+	// Description is the description of the schema definition, if it has one.
+	Description string
+
 	Query        *Definition
 	Mutation     *Definition
 	Subscription *Definition
@@ -31,11 +72,36 @@ type Schema struct {
 	Types      map[string]*Definition
 	Directives map[string]*DirectiveDefinition
 
+	// SchemaDirectives are the directives applied to the schema definition and its extensions, eg
+	// `extend schema @link(url: "...")`.
+	SchemaDirectives DirectiveList
+
 	PossibleTypes map[string][]*Definition
 	Implements    map[string][]*Definition
+
+	frozen bool `dump:"-"`
+}
+
+// Freeze marks the schema as done, eg before sharing it between goroutines. The helpers that change a schema,
+// AddPossibleType, AddImplements, StripDescriptions and validator.AddDefinition and AddDirective, panic on a
+// frozen schema, to catch changes that would race with readers. Writing to its maps directly isn't caught.
+func (s *Schema) Freeze() {
+	s.frozen = true
+}
+
+// Frozen reports whether Freeze has been called.
+func (s *Schema) Frozen() bool {
+	return s.frozen
+}
+
+func (s *Schema) mustNotBeFrozen() {
+	if s.frozen {
+		panic("ast: cannot change a frozen schema")
+	}
 }
 
 func (s *Schema) AddPossibleType(name string, def *Definition) {
+	s.mustNotBeFrozen()
 	s.PossibleTypes[name] = append(s.PossibleTypes[name], def)
 }
 
@@ -45,6 +111,7 @@ func (s *Schema) GetPossibleTypes(def *Definition) []*Definition {
 }
 
 func (s *Schema) AddImplements(name string, iface *Definition) {
+	s.mustNotBeFrozen()
 	s.Implements[name] = append(s.Implements[name], iface)
 }
 
@@ -53,6 +120,22 @@ func (s *Schema) GetImplements(def *Definition) []*Definition {
 	return s.Implements[def.Name]
 }
 
+// IsOneOf reports whether def is a @oneOf input object. Only the built in @oneOf directive counts, so a schema
+// loaded without the prelude that defines its own @oneOf doesn't get its semantics.
+func (s *Schema) IsOneOf(def *Definition) bool {
+	dir := s.Directives["oneOf"]
+	return dir != nil && dir.BuiltIn && def.Directives.ForName("oneOf") != nil
+}
+
+// TypeFromAST returns the definition of the named type at the bottom of a possibly wrapped type reference, eg
+// User for [User!]!, or nil if the schema doesn't define it.
+func (s *Schema) TypeFromAST(t *Type) *Definition {
+	if t == nil {
+		return nil
+	}
+	return s.Types[t.Name()]
+}
+
 type SchemaDefinition struct {
 	Description    string
 	Directives     DirectiveList
@@ -65,3 +148,28 @@ type OperationTypeDefinition struct {
 	Type      string
 	Position  *Position `dump:"-"`
 }
+
+// StripDescriptions removes the descriptions of the schema itself and of every type, field, argument, enum value
+// and directive in it, eg to make it smaller before sending it somewhere documentation isn't needed.
+func StripDescriptions(schema *Schema) {
+	schema.mustNotBeFrozen()
+	schema.Description = ""
+	for _, def := range schema.Types {
+		def.Description = ""
+		for _, field := range def.Fields {
+			field.Description = ""
+			for _, arg := range field.Arguments {
+				arg.Description = ""
+			}
+		}
+		for _, value := range def.EnumValues {
+			value.Description = ""
+		}
+	}
+	for _, dir := range schema.Directives {
+		dir.Description = ""
+		for _, arg := range dir.Arguments {
+			arg.Description = ""
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package ast_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/formatter"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
 	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+	_ "github.com/dgraph-io/gqlparser/v2/validator/rules"
 )
 
 func TestQueryDocMethods(t *testing.T) {
@@ -30,6 +36,39 @@ func TestQueryDocMethods(t *testing.T) {
 	})
 }
 
+func TestQueryDocOperationSelection(t *testing.T) {
+	parse := func(input string) *QueryDocument {
+		doc, err := parser.ParseQuery(&Source{Input: input})
+		require.Nil(t, err)
+		return doc
+	}
+
+	single := parse(`{ foo }`)
+	require.Equal(t, single.Operations[0], single.Operation(""))
+	require.Equal(t, single.Operations[0], single.FirstOperation())
+	op, err := single.GetOperation("")
+	require.NoError(t, err)
+	require.Equal(t, single.Operations[0], op)
+	_, err = single.GetOperation("Bob")
+	require.EqualError(t, err, `unknown operation named "Bob"`)
+
+	multiple := parse(`query Bob { foo } mutation Alice { bar }`)
+	require.Nil(t, multiple.Operation(""))
+	require.Equal(t, "Alice", multiple.Operation("Alice").Name)
+	require.Equal(t, "Bob", multiple.FirstOperation().Name)
+	op, err = multiple.GetOperation("Alice")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", op.Name)
+	_, err = multiple.GetOperation("")
+	require.EqualError(t, err, "must provide operation name if query contains multiple operations")
+
+	fragments := parse(`fragment Frag on Foo { bar }`)
+	require.Nil(t, fragments.Operation(""))
+	require.Nil(t, fragments.FirstOperation())
+	_, err = fragments.GetOperation("")
+	require.EqualError(t, err, "document does not contain any operations")
+}
+
 func TestNamedTypeCompatability(t *testing.T) {
 	assert.True(t, NamedType("A", nil).IsCompatible(NamedType("A", nil)))
 	assert.False(t, NamedType("A", nil).IsCompatible(NamedType("B", nil)))
@@ -52,3 +91,133 @@ func TestNamedTypeCompatability(t *testing.T) {
 	assert.True(t, ListType(NonNullNamedType("String", nil), nil).IsCompatible(ListType(NamedType("String", nil), nil)))
 	assert.False(t, ListType(NamedType("String", nil), nil).IsCompatible(ListType(NonNullNamedType("String", nil), nil)))
 }
+
+func TestTypeEquals(t *testing.T) {
+	assert.True(t, NamedType("A", nil).Equals(NamedType("A", &Position{Line: 2})))
+	assert.False(t, NamedType("A", nil).Equals(NamedType("B", nil)))
+	assert.False(t, NamedType("A", nil).Equals(NonNullNamedType("A", nil)))
+	assert.True(t, NonNullListType(NonNullNamedType("A", nil), nil).Equals(NonNullListType(NonNullNamedType("A", nil), nil)))
+	assert.False(t, NonNullListType(NonNullNamedType("A", nil), nil).Equals(NonNullListType(NamedType("A", nil), nil)))
+	assert.False(t, ListType(NamedType("A", nil), nil).Equals(NamedType("A", nil)))
+	assert.False(t, ListType(ListType(NamedType("A", nil), nil), nil).Equals(ListType(NamedType("A", nil), nil)))
+}
+
+func TestTypeStringRoundTrip(t *testing.T) {
+	for _, typ := range []*Type{
+		NamedType("Int", nil),
+		NonNullNamedType("Int", nil),
+		ListType(NonNullNamedType("Int", nil), nil),
+		NonNullListType(ListType(NonNullNamedType("Int", nil), nil), nil),
+	} {
+		doc, err := parser.ParseQuery(&Source{Input: "query ($v: " + typ.String() + ") { f }"})
+		require.Nil(t, err)
+		parsed := doc.Operations[0].VariableDefinitions[0].Type
+		assert.True(t, typ.Equals(parsed), typ.String())
+		assert.Equal(t, typ.String(), parsed.String())
+	}
+}
+
+func TestStripDescriptions(t *testing.T) {
+	schema, gerr := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: `
+		"The API"
+		schema { query: Query }
+		"The query root"
+		type Query {
+			"Look up a user"
+			user("The user id" id: ID!): User
+		}
+		"""
+		A user
+		of the system
+		"""
+		type User {
+			name: String
+			role: Role
+		}
+		"A role"
+		enum Role {
+			"Can do anything"
+			ADMIN
+		}
+		"Marks a cached field"
+		directive @cached("Seconds" maxAge: Int) on FIELD_DEFINITION
+	`})
+	require.Nil(t, gerr)
+	require.Equal(t, "The API", schema.Description)
+
+	StripDescriptions(schema)
+
+	require.Equal(t, "", schema.Description)
+	require.Equal(t, "", schema.Types["Query"].Description)
+	require.Equal(t, "", schema.Types["Query"].Fields.ForName("user").Description)
+	require.Equal(t, "", schema.Types["Query"].Fields.ForName("user").Arguments.ForName("id").Description)
+	require.Equal(t, "", schema.Types["Role"].EnumValues.ForName("ADMIN").Description)
+	require.Equal(t, "", schema.Directives["cached"].Description)
+	require.Equal(t, "", schema.Directives["cached"].Arguments.ForName("maxAge").Description)
+	require.Equal(t, "", schema.Types["String"].Description)
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(schema)
+	require.False(t, strings.Contains(buf.String(), `"`), buf.String())
+
+	reloaded, gerr := validator.LoadSchema(validator.Prelude, &Source{Name: "stripped", Input: buf.String()})
+	require.Nil(t, gerr)
+	query, gerr := parser.ParseQuery(&Source{Input: `{ user(id: "1") { name role } }`})
+	require.Nil(t, gerr)
+	require.Empty(t, validator.Validate(reloaded, query, nil))
+}
+
+func TestTypeFromAST(t *testing.T) {
+	schema, gerr := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: `
+		type Query { users: [User!]! }
+		type User { name: String }
+	`})
+	require.Nil(t, gerr)
+
+	users := schema.Query.Fields.ForName("users")
+	require.Equal(t, "[User!]!", users.Type.String())
+	require.Equal(t, schema.Types["User"], schema.TypeFromAST(users.Type))
+	require.Equal(t, schema.Types["String"], schema.TypeFromAST(NamedType("String", nil)))
+	require.Equal(t, schema.Types["User"], schema.TypeFromAST(ListType(ListType(NonNullNamedType("User", nil), nil), nil)))
+	require.Nil(t, schema.TypeFromAST(NonNullNamedType("Missing", nil)))
+	require.Nil(t, schema.TypeFromAST(nil))
+}
+
+func TestSchemaFreeze(t *testing.T) {
+	schema, gerr := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: `
+		type Query { user: User }
+		"A user."
+		type User { name: String }
+	`})
+	require.Nil(t, gerr)
+	require.False(t, schema.Frozen())
+
+	schema.Freeze()
+	require.True(t, schema.Frozen())
+
+	user := schema.Types["User"]
+	require.PanicsWithValue(t, "ast: cannot change a frozen schema", func() { schema.AddPossibleType("User", user) })
+	require.PanicsWithValue(t, "ast: cannot change a frozen schema", func() { schema.AddImplements("User", user) })
+	require.PanicsWithValue(t, "ast: cannot change a frozen schema", func() { StripDescriptions(schema) })
+	require.PanicsWithValue(t, "validator: cannot add a definition to a frozen schema", func() {
+		_ = validator.AddDefinition(schema, &Definition{Kind: Scalar, Name: "Time"})
+	})
+	require.PanicsWithValue(t, "validator: cannot add a directive to a frozen schema", func() {
+		_ = validator.AddDirective(schema, &DirectiveDefinition{Name: "tag", Locations: []DirectiveLocation{LocationObject}})
+	})
+	require.Nil(t, schema.Types["Time"])
+	require.Equal(t, []*Definition{user}, schema.GetPossibleTypes(user))
+	require.Equal(t, "A user.", user.Description)
+
+	// a frozen schema can still be read, and validated against, from many goroutines at once
+	done := make(chan gqlerror.List)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, errs := validator.LoadQuery(schema, `{ user { name __typename } }`, validator.DefaultRuleSet())
+			done <- errs
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		require.Empty(t, <-done)
+	}
+}
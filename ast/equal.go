@@ -0,0 +1,194 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// EqualOption configures Equal and Mismatch
+type EqualOption func(c *comparer)
+
+// WithPositions makes Equal and Mismatch compare the Start, End, Line and Column of positions as well. The
+// Source a position points at is never compared.
+func WithPositions() EqualOption {
+	return func(c *comparer) {
+		c.positions = true
+	}
+}
+
+// Equal reports whether a and b are the same ast, ignoring positions and the other metadata that Dump leaves
+// out. The references into the schema filled in by validation, such as Field.Definition, are skipped, so a
+// document is equal to itself whether or not it was validated, and against which schema.
+func Equal(a, b interface{}, opts ...EqualOption) bool {
+	return Mismatch(a, b, opts...) == ""
+}
+
+// Mismatch describes the first difference between a and b, as found by Equal, prefixed with the path to it,
+// eg `SelectionSet[0].Alias: "a" != "b"`. It returns an empty string when they are equal.
+func Mismatch(a, b interface{}, opts ...EqualOption) string {
+	c := comparer{visited: map[visit]bool{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c.compare("", reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+type comparer struct {
+	positions bool
+	visited   map[visit]bool
+}
+
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+var positionType = reflect.TypeOf(Position{})
+
+// validationReferences are the fields validation fills in, by struct and field name, which Equal skips
+var validationReferences = map[string]bool{
+	"Field.Definition":                true,
+	"Field.ObjectDefinition":          true,
+	"Value.Definition":                true,
+	"Value.VariableDefinition":        true,
+	"Value.ExpectedType":              true,
+	"Directive.ParentDefinition":      true,
+	"Directive.Definition":            true,
+	"FragmentSpread.ObjectDefinition": true,
+	"FragmentSpread.Definition":       true,
+	"InlineFragment.ObjectDefinition": true,
+	"FragmentDefinition.Definition":   true,
+	"VariableDefinition.Definition":   true,
+}
+
+func (c *comparer) compare(path string, a, b reflect.Value) string {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			return c.mismatch(path, a, b)
+		}
+		return ""
+	}
+	if a.Type() != b.Type() {
+		return fmt.Sprintf("%s: %s != %s", pathOrRoot(path), a.Type(), b.Type())
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return c.mismatch(path, a, b)
+			}
+			return ""
+		}
+		if a.Kind() == reflect.Ptr {
+			// schemas reference themselves through directives, so only compare each pair of pointers once
+			v := visit{a.Pointer(), b.Pointer(), a.Type()}
+			if v.a == v.b || c.visited[v] {
+				return ""
+			}
+			c.visited[v] = true
+		}
+		return c.compare(path, a.Elem(), b.Elem())
+
+	case reflect.Array, reflect.Slice:
+		if a.Len() != b.Len() {
+			return fmt.Sprintf("%s: length %d != %d", pathOrRoot(path), a.Len(), b.Len())
+		}
+		for i := 0; i < a.Len(); i++ {
+			if m := c.compare(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i)); m != "" {
+				return m
+			}
+		}
+		return ""
+
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			k := keys[name]
+			if m := c.compare(path+"["+strconv.Quote(name)+"]", a.MapIndex(k), b.MapIndex(k)); m != "" {
+				return m
+			}
+		}
+		return ""
+
+	case reflect.Struct:
+		if a.Type() == positionType {
+			return c.comparePosition(path, a, b)
+		}
+		typ := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if field.Tag.Get("dump") == "-" && !(c.positions && field.Name == "Position") {
+				continue
+			}
+			if validationReferences[typ.Name()+"."+field.Name] {
+				continue
+			}
+			if m := c.compare(path+"."+field.Name, a.Field(i), b.Field(i)); m != "" {
+				return m
+			}
+		}
+		return ""
+
+	case reflect.Func, reflect.Chan:
+		return ""
+	}
+
+	if a.Interface() != b.Interface() {
+		return c.mismatch(path, a, b)
+	}
+	return ""
+}
+
+func (c *comparer) comparePosition(path string, a, b reflect.Value) string {
+	pa := a.Interface().(Position)
+	pb := b.Interface().(Position)
+	if pa.Start != pb.Start || pa.End != pb.End || pa.Line != pb.Line || pa.Column != pb.Column {
+		return fmt.Sprintf("%s: %d:%d (%d-%d) != %d:%d (%d-%d)", pathOrRoot(path),
+			pa.Line, pa.Column, pa.Start, pa.End, pb.Line, pb.Column, pb.Start, pb.End)
+	}
+	return ""
+}
+
+func (c *comparer) mismatch(path string, a, b reflect.Value) string {
+	return fmt.Sprintf("%s: %s != %s", pathOrRoot(path), describe(a), describe(b))
+}
+
+func describe(v reflect.Value) string {
+	if !v.IsValid() {
+		return "missing"
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return typeName(v.Elem().Type())
+	case reflect.String:
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	if path[0] == '.' {
+		return path[1:]
+	}
+	return path
+}
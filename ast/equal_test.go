@@ -0,0 +1,80 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestEqual(t *testing.T) {
+	parse := func(input string) *QueryDocument {
+		doc, err := parser.ParseQuery(&Source{Input: input})
+		require.Nil(t, err)
+		return doc
+	}
+
+	t.Run("ignores positions by default", func(t *testing.T) {
+		a := parse(`query Q($id: ID = 1) { user(id: $id) { name ...F } } fragment F on User { id }`)
+		b := parse("query Q(\n  $id: ID = 1\n) {\n  user(id: $id) {\n    name\n    ...F\n  }\n}\n\nfragment F on User {\n  id\n}")
+
+		require.True(t, Equal(a, b))
+		require.Equal(t, "", Mismatch(a, b))
+		require.Equal(t, "Operations[0].VariableDefinitions[0].Type.Position: 1:14 (13-15) != 2:8 (16-18)",
+			Mismatch(a, b, WithPositions()))
+	})
+
+	t.Run("compares positions when asked", func(t *testing.T) {
+		a := parse(`{ name }`)
+		b := parse(`{ name }`)
+		require.True(t, Equal(a, b, WithPositions()))
+	})
+
+	t.Run("describes the first mismatch", func(t *testing.T) {
+		require.Equal(t, `Operations[0].SelectionSet[0].Alias: "a" != "b"`,
+			Mismatch(parse(`{ a: name }`), parse(`{ b: name }`)))
+		require.Equal(t, `Operations[0].SelectionSet[1]: *ast.Field != *ast.FragmentSpread`,
+			Mismatch(parse(`{ name id }`), parse(`{ name ...F }`)))
+		require.Equal(t, `Operations[0].SelectionSet: length 1 != 2`,
+			Mismatch(parse(`{ name }`), parse(`{ name id }`)))
+		require.Equal(t, `Operations[0].SelectionSet[0].Arguments[0].Value.Kind: 1 != 3`,
+			Mismatch(parse(`{ user(id: 1) }`), parse(`{ user(id: "1") }`)))
+		require.Equal(t, `Operations[0].SelectionSet[0].SelectionSet: length 1 != 0`,
+			Mismatch(parse(`{ user { id } }`), parse(`{ user }`)))
+		require.Equal(t, `<root>: nil != QueryDocument`, Mismatch((*QueryDocument)(nil), parse(`{ name }`)))
+	})
+
+	t.Run("validated documents and schemas", func(t *testing.T) {
+		load := func(input string) *Schema {
+			schema, err := validator.LoadSchema(validator.Prelude, &Source{Input: input})
+			require.Nil(t, err)
+			return schema
+		}
+		input := `
+			directive @tag on OBJECT
+			type Query @tag { user(id: ID): User }
+			type User @tag { id: ID! name: String }
+		`
+		a, b := load(input), load(input)
+		require.True(t, Equal(a, b))
+
+		queryA := parse(`{ user(id: 1) { id name } }`)
+		queryB := parse(`{ user(id: 1) { id name } }`)
+		require.Nil(t, validator.Validate(a, queryA, nil))
+		require.Nil(t, validator.Validate(b, queryB, nil))
+		require.True(t, Equal(queryA, queryB))
+
+		c := load(strings.Replace(input, "name: String", "name: String!", 1))
+		require.Equal(t, `Types["User"].Fields[1].Type.NonNull: false != true`, Mismatch(a, c))
+
+		// the references validation filled in point at different schemas, but the documents are the same
+		queryC := parse(`{ user(id: 1) { id name } }`)
+		require.Nil(t, validator.Validate(c, queryC, nil))
+		require.True(t, Equal(queryA, queryC), Mismatch(queryA, queryC))
+		require.True(t, Equal(queryA, parse(`{ user(id: 1) { id name } }`)))
+	})
+}
@@ -36,3 +36,37 @@ type FragmentDefinition struct {
 
 	Position *Position `dump:"-"`
 }
+
+// FragmentsUsedBy returns the fragments op spreads, directly or through other fragments, in the order they are
+// first spread. Spreads of fragments that aren't defined in doc are skipped, and each fragment is returned once,
+// even when fragments spread each other in a cycle.
+func FragmentsUsedBy(op *OperationDefinition, doc *QueryDocument) []*FragmentDefinition {
+	var used []*FragmentDefinition
+	seen := map[string]bool{}
+
+	var visit func(set SelectionSet)
+	visit = func(set SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *Field:
+				visit(sel.SelectionSet)
+			case *InlineFragment:
+				visit(sel.SelectionSet)
+			case *FragmentSpread:
+				if seen[sel.Name] {
+					continue
+				}
+				seen[sel.Name] = true
+				def := doc.Fragments.ForName(sel.Name)
+				if def == nil {
+					continue
+				}
+				used = append(used, def)
+				visit(def.SelectionSet)
+			}
+		}
+	}
+	visit(op.SelectionSet)
+
+	return used
+}
@@ -0,0 +1,48 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+)
+
+func TestFragmentsUsedBy(t *testing.T) {
+	used := func(doc *QueryDocument, op string) []string {
+		var names []string
+		for _, def := range FragmentsUsedBy(doc.Operations.ForName(op), doc) {
+			names = append(names, def.Name)
+		}
+		return names
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&Source{Input: `
+			query A { user { ...UserFields ... on User { friends { ...Avatar } } } }
+			query B { user { ...Avatar } }
+			query C { name }
+			fragment UserFields on User { name ...Friends }
+			fragment Friends on User { friends { ...Avatar ...UserFields } }
+			fragment Avatar on User { avatar }
+			fragment Unused on User { name }
+		`})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"UserFields", "Friends", "Avatar"}, used(doc, "A"))
+		require.Equal(t, []string{"Avatar"}, used(doc, "B"))
+		require.Empty(t, used(doc, "C"))
+	})
+
+	t.Run("cyclic and undefined", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&Source{Input: `
+			query A { ...X }
+			fragment X on Query { ...Y ...Missing }
+			fragment Y on Query { ...X ...Y }
+		`})
+		require.Nil(t, err)
+
+		require.Equal(t, []string{"X", "Y"}, used(doc, "A"))
+	})
+}
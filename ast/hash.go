@@ -0,0 +1,185 @@
+package ast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OperationHash returns a hex encoded SHA-256 of the canonical form of an operation, for use as a persisted
+// query or cache key. Fragments spread by the operation are resolved through the spread's Definition, or by
+// name from fragments, and hashed along with it.
+//
+// The canonical form ignores whitespace, commas, comments and the source position of every node. In addition:
+//   - the selections of every selection set are sorted, so reordering fields, fragment spreads and inline
+//     fragments doesn't change the hash. Aliases are kept, as they change the shape of the response.
+//   - variable definitions, arguments and input object fields are sorted by name.
+//   - block strings and quoted strings with the same value are equivalent.
+//
+// Directive order and list element order are significant and kept as is. Unknown fragment spreads are hashed
+// by name only.
+func OperationHash(op *OperationDefinition, fragments ...*FragmentDefinition) string {
+	c := canonicalizer{
+		fragments: fragments,
+		used:      map[string]*FragmentDefinition{},
+	}
+	c.operation(op)
+
+	var names []string
+	for name := range c.used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.fragment(c.used[name])
+	}
+
+	sum := sha256.Sum256([]byte(c.sb.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+type canonicalizer struct {
+	sb        strings.Builder
+	fragments []*FragmentDefinition
+	used      map[string]*FragmentDefinition
+}
+
+func (c *canonicalizer) operation(op *OperationDefinition) {
+	operation := op.Operation
+	if operation == "" {
+		operation = Query
+	}
+	c.sb.WriteString(string(operation))
+	if op.Name != "" {
+		c.sb.WriteString(" " + op.Name)
+	}
+
+	if len(op.VariableDefinitions) != 0 {
+		vars := make([]string, 0, len(op.VariableDefinitions))
+		for _, v := range op.VariableDefinitions {
+			s := "$" + v.Variable + ":" + v.Type.String()
+			if v.DefaultValue != nil {
+				s += "=" + canonicalValue(v.DefaultValue)
+			}
+			s += canonicalDirectives(v.Directives)
+			vars = append(vars, s)
+		}
+		sort.Strings(vars)
+		c.sb.WriteString("(" + strings.Join(vars, ",") + ")")
+	}
+
+	c.sb.WriteString(canonicalDirectives(op.Directives))
+	c.sb.WriteString(c.selectionSet(op.SelectionSet))
+	c.sb.WriteString("\n")
+}
+
+func (c *canonicalizer) fragment(def *FragmentDefinition) {
+	c.sb.WriteString("fragment " + def.Name + " on " + def.TypeCondition)
+	c.sb.WriteString(canonicalDirectives(def.Directives))
+	c.sb.WriteString(c.selectionSet(def.SelectionSet))
+	c.sb.WriteString("\n")
+}
+
+func (c *canonicalizer) selectionSet(set SelectionSet) string {
+	if len(set) == 0 {
+		return ""
+	}
+
+	selections := make([]string, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			s := sel.Name
+			if sel.Alias != "" && sel.Alias != sel.Name {
+				s = sel.Alias + ":" + s
+			}
+			s += canonicalArguments(sel.Arguments)
+			s += canonicalDirectives(sel.Directives)
+			s += c.selectionSet(sel.SelectionSet)
+			selections = append(selections, s)
+
+		case *InlineFragment:
+			s := "..."
+			if sel.TypeCondition != "" {
+				s += " on " + sel.TypeCondition
+			}
+			s += canonicalDirectives(sel.Directives)
+			s += c.selectionSet(sel.SelectionSet)
+			selections = append(selections, s)
+
+		case *FragmentSpread:
+			c.useFragment(sel)
+			selections = append(selections, "..."+sel.Name+canonicalDirectives(sel.Directives))
+		}
+	}
+	sort.Strings(selections)
+
+	return "{" + strings.Join(selections, " ") + "}"
+}
+
+func (c *canonicalizer) useFragment(spread *FragmentSpread) {
+	if _, ok := c.used[spread.Name]; ok {
+		return
+	}
+
+	def := spread.Definition
+	if def == nil {
+		for _, fragment := range c.fragments {
+			if fragment.Name == spread.Name {
+				def = fragment
+				break
+			}
+		}
+	}
+	if def == nil {
+		return
+	}
+
+	c.used[spread.Name] = def
+	// collect the fragments this one spreads, the selection string itself is discarded
+	c.selectionSet(def.SelectionSet)
+}
+
+func canonicalDirectives(directives DirectiveList) string {
+	var sb strings.Builder
+	for _, d := range directives {
+		sb.WriteString("@" + d.Name + canonicalArguments(d.Arguments))
+	}
+	return sb.String()
+}
+
+func canonicalArguments(args ArgumentList) string {
+	if len(args) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(args))
+	for _, arg := range args {
+		list = append(list, arg.Name+":"+canonicalValue(arg.Value))
+	}
+	sort.Strings(list)
+	return "(" + strings.Join(list, ",") + ")"
+}
+
+func canonicalValue(v *Value) string {
+	switch v.Kind {
+	case StringValue, BlockValue:
+		return strconv.Quote(v.Raw)
+	case ListValue:
+		list := make([]string, 0, len(v.Children))
+		for _, child := range v.Children {
+			list = append(list, canonicalValue(child.Value))
+		}
+		return "[" + strings.Join(list, ",") + "]"
+	case ObjectValue:
+		list := make([]string, 0, len(v.Children))
+		for _, child := range v.Children {
+			list = append(list, child.Name+":"+canonicalValue(child.Value))
+		}
+		sort.Strings(list)
+		return "{" + strings.Join(list, ",") + "}"
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,60 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+)
+
+func TestOperationHash(t *testing.T) {
+	hash := func(input string) string {
+		doc, err := parser.ParseQuery(&Source{Input: input})
+		require.Nil(t, err)
+		return OperationHash(doc.Operations[0], doc.Fragments...)
+	}
+
+	base := hash(`query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id name ...F } } fragment F on User { email }`)
+	require.Len(t, base, 64)
+
+	for name, input := range map[string]string{
+		"formatting": `
+			# fetch a user
+			query Q(
+				$id: ID!
+				$n: Int = 1
+			) {
+				user(id: $id, filter: {a: 1, b: """x"""}) {
+					id
+					name
+					...F
+				}
+			}
+			fragment F on User { email }`,
+		"reordered": `query Q($n: Int = 1, $id: ID!) { user(filter: {b: "x", a: 1}, id: $id) { ...F name id } } fragment F on User { email }`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, base, hash(input))
+		})
+	}
+
+	for name, input := range map[string]string{
+		"field":     `query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id email ...F } } fragment F on User { email }`,
+		"argument":  `query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 2, b: "x"}) { id name ...F } } fragment F on User { email }`,
+		"alias":     `query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id fullName: name ...F } } fragment F on User { email }`,
+		"default":   `query Q($id: ID!, $n: Int = 2) { user(id: $id, filter: {a: 1, b: "x"}) { id name ...F } } fragment F on User { email }`,
+		"name":      `query R($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id name ...F } } fragment F on User { email }`,
+		"fragment":  `query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id name ...F } } fragment F on User { phone }`,
+		"directive": `query Q($id: ID!, $n: Int = 1) { user(id: $id, filter: {a: 1, b: "x"}) { id name @skip(if: true) ...F } } fragment F on User { email }`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NotEqual(t, base, hash(input))
+		})
+	}
+
+	t.Run("recursive fragments", func(t *testing.T) {
+		require.NotEmpty(t, hash(`{ ...A } fragment A on Query { ...B } fragment B on Query { ...A }`))
+	})
+}
@@ -37,3 +37,51 @@ type Argument struct {
 func (f *Field) ArgumentMap(vars map[string]interface{}) map[string]interface{} {
 	return arg2map(f.Definition.Arguments, f.Arguments, vars)
 }
+
+// SelectionTree turns a selection set into nested maps of response key to sub tree, with nil for leaf fields.
+// Inline fragments are merged into their parent, as are fragment spreads when their definition is known,
+// either from validation or from the given fragments.
+func SelectionTree(set SelectionSet, fragments ...*FragmentDefinition) map[string]interface{} {
+	tree := map[string]interface{}{}
+	buildSelectionTree(tree, set, FragmentDefinitionList(fragments), map[string]bool{})
+	return tree
+}
+
+func buildSelectionTree(tree map[string]interface{}, set SelectionSet, fragments FragmentDefinitionList, visiting map[string]bool) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			key := sel.Alias
+			if key == "" {
+				key = sel.Name
+			}
+			if len(sel.SelectionSet) == 0 {
+				if _, ok := tree[key]; !ok {
+					tree[key] = nil
+				}
+				continue
+			}
+			sub, _ := tree[key].(map[string]interface{})
+			if sub == nil {
+				sub = map[string]interface{}{}
+				tree[key] = sub
+			}
+			buildSelectionTree(sub, sel.SelectionSet, fragments, visiting)
+
+		case *InlineFragment:
+			buildSelectionTree(tree, sel.SelectionSet, fragments, visiting)
+
+		case *FragmentSpread:
+			def := sel.Definition
+			if def == nil {
+				def = fragments.ForName(sel.Name)
+			}
+			if def == nil || visiting[def.Name] {
+				continue
+			}
+			visiting[def.Name] = true
+			buildSelectionTree(tree, def.SelectionSet, fragments, visiting)
+			delete(visiting, def.Name)
+		}
+	}
+}
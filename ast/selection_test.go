@@ -0,0 +1,52 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+)
+
+func TestSelectionTree(t *testing.T) {
+	doc, err := parser.ParseQuery(&Source{Input: `
+		{
+			user {
+				id
+				friends: following { name }
+				... on User { email }
+				...UserFields
+			}
+			unknown { ...Missing }
+		}
+		fragment UserFields on User {
+			id
+			friends: following { id }
+			...UserFields
+		}
+	`})
+	require.Nil(t, err)
+
+	t.Run("nested selections", func(t *testing.T) {
+		require.Equal(t, map[string]interface{}{
+			"user": map[string]interface{}{
+				"id":      nil,
+				"friends": map[string]interface{}{"name": nil},
+				"email":   nil,
+			},
+			"unknown": map[string]interface{}{},
+		}, SelectionTree(doc.Operations[0].SelectionSet))
+	})
+
+	t.Run("fragment expansion", func(t *testing.T) {
+		require.Equal(t, map[string]interface{}{
+			"user": map[string]interface{}{
+				"id":      nil,
+				"friends": map[string]interface{}{"name": nil, "id": nil},
+				"email":   nil,
+			},
+			"unknown": map[string]interface{}{},
+		}, SelectionTree(doc.Operations[0].SelectionSet, doc.Fragments...))
+	})
+}
@@ -1,5 +1,13 @@
 package ast
 
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
 // Source covers a single *.graphql file
 type Source struct {
 	// Name is the filename of the source
@@ -8,6 +16,112 @@ type Source struct {
 	Input string
 	// BuiltIn indicate whether the source is a part of the specification
 	BuiltIn bool
+	// Segments lists where each original file starts when Input is several files concatenated together, in
+	// order of StartLine. See ConcatSources and MapPosition.
+	Segments []SourceSegment
+
+	// the *lineIndex built by LineColumn. An atomic.Value, unlike a sync.Once, lets Source be copied by value
+	lines atomic.Value
+}
+
+type lineIndex struct {
+	input     string // the Input the index was built for
+	starts    []int  // rune offset of the start of every line
+	runeCount int
+}
+
+// SourceSegment marks the start of an original file inside a concatenated Source.
+type SourceSegment struct {
+	// Name is the filename of the original file
+	Name string
+	// StartLine is the 1 indexed line of the concatenated Source the file starts on
+	StartLine int
+}
+
+// ConcatSources joins sources into a single Source called name, so they can be parsed in one go, and records
+// a segment for each of them so positions can be mapped back with MapPosition. A newline is added after any
+// source that doesn't end with one.
+func ConcatSources(name string, sources ...*Source) *Source {
+	var buf strings.Builder
+	concat := &Source{Name: name}
+	line := 1
+	for _, src := range sources {
+		concat.Segments = append(concat.Segments, SourceSegment{Name: src.Name, StartLine: line})
+
+		input := src.Input
+		if input != "" && input[len(input)-1] != '\n' && input[len(input)-1] != '\r' {
+			input += "\n"
+		}
+		buf.WriteString(input)
+		line += strings.Count(input, "\n") + strings.Count(input, "\r") - strings.Count(input, "\r\n")
+	}
+	concat.Input = buf.String()
+	return concat
+}
+
+// MapLine returns the original file and line for a 1 indexed line of s. Sources without segments, and lines
+// before the first segment, map to s itself.
+func (s *Source) MapLine(line int) (file string, originalLine int) {
+	i := sort.Search(len(s.Segments), func(i int) bool {
+		return s.Segments[i].StartLine > line
+	})
+	if i == 0 {
+		return s.Name, line
+	}
+	seg := s.Segments[i-1]
+	return seg.Name, line - seg.StartLine + 1
+}
+
+// MapPosition returns the original file and line of a position in a Source built by ConcatSources, or the
+// name of its source and its line when the source wasn't concatenated.
+func (s *Source) MapPosition(p *Position) (file string, line int) {
+	return s.MapLine(p.Line)
+}
+
+// LineColumn returns the 1 indexed line and column of a rune offset into Input, as used by Position.Start and
+// Position.End. Like the lexer it treats \n, \r\n and \r as line terminators. Offsets outside of Input are
+// clamped to its start or end.
+//
+// The first call indexes the start of every line in Input, later calls are a binary search of that index. The
+// index is built again if Input changes.
+func (s *Source) LineColumn(runeOffset int) (line, col int) {
+	idx, _ := s.lines.Load().(*lineIndex)
+	if idx == nil || idx.input != s.Input {
+		idx = indexLines(s.Input)
+		s.lines.Store(idx)
+	}
+
+	if runeOffset < 0 {
+		runeOffset = 0
+	}
+	if runeOffset > idx.runeCount {
+		runeOffset = idx.runeCount
+	}
+
+	// the last line starting at or before the offset
+	line = sort.Search(len(idx.starts), func(i int) bool {
+		return idx.starts[i] > runeOffset
+	})
+	return line, runeOffset - idx.starts[line-1] + 1
+}
+
+func indexLines(input string) *lineIndex {
+	idx := &lineIndex{input: input, starts: []int{0}}
+	runes := 0
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		i += size
+		runes++
+		if r == '\r' && i < len(input) && input[i] == '\n' {
+			i++
+			runes++
+		}
+		if r == '\n' || r == '\r' {
+			idx.starts = append(idx.starts, runes)
+		}
+	}
+	idx.runeCount = runes
+	return idx
 }
 
 type Position struct {
@@ -17,3 +131,76 @@ type Position struct {
 	Column int     // The column number at the start of this item.
 	Src    *Source // The source document this token belongs to
 }
+
+// String returns the position as "name:line:column", omitting the name if the source doesn't have one.
+func (p *Position) String() string {
+	loc := strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
+	if p.Src != nil && p.Src.Name != "" {
+		return p.Src.Name + ":" + loc
+	}
+	return loc
+}
+
+// Snippet returns the source line containing the position, and an underline with carets under the
+// token from Start to End. Tokens spanning multiple lines are underlined up to the end of their first line, and
+// positions without an end get a single caret.
+func (p *Position) Snippet() (line string, underline string) {
+	if p == nil || p.Src == nil {
+		return "", ""
+	}
+
+	line = sourceLine(p.Src.Input, p.Line)
+	runes := []rune(line)
+
+	col := p.Column - 1
+	if col < 0 {
+		col = 0
+	}
+	if col > len(runes) {
+		col = len(runes)
+	}
+
+	width := p.End - p.Start
+	if col+width > len(runes) {
+		width = len(runes) - col
+	}
+	if width < 1 {
+		width = 1
+	}
+
+	var buf strings.Builder
+	for _, r := range runes[:col] {
+		// keep tabs so the carets line up with the source line
+		if r == '\t' {
+			buf.WriteRune('\t')
+		} else {
+			buf.WriteByte(' ')
+		}
+	}
+	buf.WriteString(strings.Repeat("^", width))
+
+	return line, buf.String()
+}
+
+// sourceLine returns the given 1 indexed line of input, treating \n, \r\n and \r as line terminators.
+func sourceLine(input string, n int) string {
+	line := 1
+	start := 0
+	for i := 0; i < len(input); i++ {
+		if input[i] != '\n' && input[i] != '\r' {
+			continue
+		}
+		if line == n {
+			return input[start:i]
+		}
+		if input[i] == '\r' && i+1 < len(input) && input[i+1] == '\n' {
+			i++
+		}
+		line++
+		start = i + 1
+	}
+	if line == n {
+		return input[start:]
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_String(t *testing.T) {
+	require.Equal(t, "schema.graphql:3:7", (&Position{Line: 3, Column: 7, Src: &Source{Name: "schema.graphql"}}).String())
+	require.Equal(t, "3:7", (&Position{Line: 3, Column: 7, Src: &Source{}}).String())
+	require.Equal(t, "3:7", (&Position{Line: 3, Column: 7}).String())
+}
+
+func TestPosition_Snippet(t *testing.T) {
+	src := &Source{Input: "type Query {\r\n\tname: Strng\r\n}\n\"\"\"multi\nline\"\"\""}
+
+	t.Run("token", func(t *testing.T) {
+		line, underline := (&Position{Start: 21, End: 26, Line: 2, Column: 8, Src: src}).Snippet()
+		require.Equal(t, "\tname: Strng", line)
+		require.Equal(t, "\t      ^^^^^", underline)
+	})
+
+	t.Run("without width", func(t *testing.T) {
+		line, underline := (&Position{Line: 1, Column: 6, Src: src}).Snippet()
+		require.Equal(t, "type Query {", line)
+		require.Equal(t, "     ^", underline)
+
+		line, underline = (&Position{Line: 1, Column: 12, Src: src}).Snippet()
+		require.Equal(t, "type Query {", line)
+		require.Equal(t, "           ^", underline)
+	})
+
+	t.Run("multi line token", func(t *testing.T) {
+		line, underline := (&Position{Start: 30, End: 48, Line: 4, Column: 1, Src: src}).Snippet()
+		require.Equal(t, `"""multi`, line)
+		require.Equal(t, "^^^^^^^^", underline)
+	})
+
+	t.Run("without source", func(t *testing.T) {
+		line, underline := (&Position{Line: 1, Column: 1}).Snippet()
+		require.Equal(t, "", line)
+		require.Equal(t, "", underline)
+	})
+}
+
+func TestSource_LineColumn(t *testing.T) {
+	// é and 日本 are multi byte, offsets and columns count runes
+	src := &Source{Input: "type Café {\r\n\t日本: String\r}\n\n"}
+
+	for _, tc := range []struct {
+		offset    int
+		line, col int
+	}{
+		{0, 1, 1},
+		{5, 1, 6},   // C
+		{8, 1, 9},   // é
+		{10, 1, 11}, // {
+		{11, 1, 12}, // \r
+		{13, 2, 1},  // \t
+		{14, 2, 2},  // 日
+		{15, 2, 3},  // 本
+		{16, 2, 4},  // :
+		{25, 3, 1},  // }
+		{27, 4, 1},  // empty line
+		{28, 5, 1},  // end of input
+		{100, 5, 1},
+		{-1, 1, 1},
+	} {
+		line, col := src.LineColumn(tc.offset)
+		require.Equal(t, []int{tc.line, tc.col}, []int{line, col}, "offset %d", tc.offset)
+	}
+
+	line, col := (&Source{}).LineColumn(3)
+	require.Equal(t, []int{1, 1}, []int{line, col})
+
+	// the index follows changes to Input
+	changed := &Source{Input: "{ab}"}
+	line, col = changed.LineColumn(3)
+	require.Equal(t, []int{1, 4}, []int{line, col})
+	changed.Input = "{\nb}"
+	line, col = changed.LineColumn(3)
+	require.Equal(t, []int{2, 2}, []int{line, col})
+
+	// copies of a Source work like the original
+	copied := *changed
+	line, col = copied.LineColumn(3)
+	require.Equal(t, []int{2, 2}, []int{line, col})
+}
+
+func TestConcatSources(t *testing.T) {
+	src := ConcatSources("schema",
+		&Source{Name: "a.graphql", Input: "type Query {\n\tuser: User\n}\n"},
+		&Source{Name: "b.graphql", Input: "type User {\r\n\tid: ID!\r\n}"},
+		&Source{Name: "c.graphql", Input: "scalar Time"},
+	)
+
+	require.Equal(t, "type Query {\n\tuser: User\n}\ntype User {\r\n\tid: ID!\r\n}\nscalar Time\n", src.Input)
+	require.Equal(t, []SourceSegment{
+		{Name: "a.graphql", StartLine: 1},
+		{Name: "b.graphql", StartLine: 4},
+		{Name: "c.graphql", StartLine: 7},
+	}, src.Segments)
+
+	for _, tc := range []struct {
+		line, originalLine int
+		file               string
+	}{
+		{1, 1, "a.graphql"},
+		{3, 3, "a.graphql"},
+		{4, 1, "b.graphql"},
+		{5, 2, "b.graphql"},
+		{7, 1, "c.graphql"},
+	} {
+		file, line := src.MapPosition(&Position{Line: tc.line, Src: src})
+		require.Equal(t, tc.file, file)
+		require.Equal(t, tc.originalLine, line)
+	}
+
+	file, line := (&Source{Name: "plain.graphql"}).MapLine(3)
+	require.Equal(t, "plain.graphql", file)
+	require.Equal(t, 3, line)
+}
@@ -63,6 +63,14 @@ func (t *Type) IsCompatible(other *Type) bool {
 	return true
 }
 
+// Equals reports whether both types are the same type reference, ignoring positions.
+func (t *Type) Equals(other *Type) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	return t.NamedType == other.NamedType && t.NonNull == other.NonNull && t.Elem.Equals(other.Elem)
+}
+
 func (v *Type) Dump() string {
 	return v.String()
 }
@@ -0,0 +1,124 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+)
+
+// UnusedSchemaFields returns the coordinates of the object and interface fields that are never selected by any
+// operation in docs, expanding fragments along the way. An interface field counts as used when the same field is
+// selected on any of its implementors, and selecting an interface field counts as using it on every implementor.
+// Built in and introspection types are ignored. The result is sorted by type and field name.
+func UnusedSchemaFields(schema *Schema, docs []*QueryDocument) []SchemaCoordinate {
+	u := usage{
+		schema: schema,
+		used:   map[string]map[string]bool{},
+	}
+	for _, doc := range docs {
+		u.fragments = doc.Fragments
+		u.expanded = map[string]bool{}
+		for _, op := range doc.Operations {
+			u.walk(u.rootType(op.Operation), op.SelectionSet)
+		}
+	}
+
+	var unused []SchemaCoordinate
+	for _, def := range schema.Types {
+		if def.BuiltIn || strings.HasPrefix(def.Name, "__") || (def.Kind != Object && def.Kind != Interface) {
+			continue
+		}
+		for _, field := range def.Fields {
+			if strings.HasPrefix(field.Name, "__") || u.isUsed(def, field.Name) {
+				continue
+			}
+			unused = append(unused, SchemaCoordinate{Type: def.Name, Member: field.Name})
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].Type != unused[j].Type {
+			return unused[i].Type < unused[j].Type
+		}
+		return unused[i].Member < unused[j].Member
+	})
+	return unused
+}
+
+type usage struct {
+	schema    *Schema
+	fragments FragmentDefinitionList
+	used      map[string]map[string]bool
+	// fragment spreads already expanded, keyed by parent type and fragment name
+	expanded map[string]bool
+}
+
+func (u *usage) rootType(op Operation) *Definition {
+	switch op {
+	case Mutation:
+		return u.schema.Mutation
+	case Subscription:
+		return u.schema.Subscription
+	default:
+		return u.schema.Query
+	}
+}
+
+func (u *usage) walk(def *Definition, set SelectionSet) {
+	if def == nil {
+		return
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			fieldDef := def.Fields.ForName(sel.Name)
+			if fieldDef == nil {
+				continue
+			}
+			if u.used[def.Name] == nil {
+				u.used[def.Name] = map[string]bool{}
+			}
+			u.used[def.Name][sel.Name] = true
+			u.walk(u.schema.TypeFromAST(fieldDef.Type), sel.SelectionSet)
+
+		case *InlineFragment:
+			if sel.TypeCondition == "" {
+				u.walk(def, sel.SelectionSet)
+			} else {
+				u.walk(u.schema.Types[sel.TypeCondition], sel.SelectionSet)
+			}
+
+		case *FragmentSpread:
+			fragment := sel.Definition
+			if fragment == nil {
+				fragment = u.fragments.ForName(sel.Name)
+			}
+			if fragment == nil {
+				continue
+			}
+			key := def.Name + "." + fragment.Name
+			if u.expanded[key] {
+				continue
+			}
+			u.expanded[key] = true
+			u.walk(u.schema.Types[fragment.TypeCondition], fragment.SelectionSet)
+		}
+	}
+}
+
+func (u *usage) isUsed(def *Definition, field string) bool {
+	if u.used[def.Name][field] {
+		return true
+	}
+	if def.Kind == Interface {
+		for _, impl := range u.schema.GetPossibleTypes(def) {
+			if u.used[impl.Name][field] {
+				return true
+			}
+		}
+	}
+	for _, iface := range u.schema.GetImplements(def) {
+		if iface.Kind == Interface && u.used[iface.Name][field] {
+			return true
+		}
+	}
+	return false
+}
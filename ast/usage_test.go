@@ -0,0 +1,62 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestUnusedSchemaFields(t *testing.T) {
+	schema, gerr := validator.LoadSchema(validator.Prelude, &Source{Name: "schema", Input: `
+		type Query {
+			node(id: ID!): Node
+			user(id: ID!): User
+			search: [SearchResult]
+		}
+		type Mutation {
+			rename(name: String!): User
+			delete(id: ID!): Boolean
+		}
+		interface Node {
+			id: ID!
+			createdAt: String
+		}
+		type User implements Node {
+			id: ID!
+			createdAt: String
+			name: String
+			email: String
+			friends: [User]
+		}
+		type Post implements Node {
+			id: ID!
+			createdAt: String
+			title: String
+		}
+		union SearchResult = User | Post
+	`})
+	require.Nil(t, gerr)
+
+	var docs []*QueryDocument
+	for _, input := range []string{
+		`query { node(id: "1") { id ... on Post { title } } }`,
+		`query { user(id: "1") { ...UserFields friends { ...UserFields } } }
+		fragment UserFields on User { name friends { ...UserFields } }`,
+		`mutation { rename(name: "x") { __typename createdAt } }`,
+	} {
+		doc, err := parser.ParseQuery(&Source{Input: input})
+		require.Nil(t, err)
+		docs = append(docs, doc)
+	}
+
+	require.Equal(t, []SchemaCoordinate{
+		{Type: "Mutation", Member: "delete"},
+		{Type: "Post", Member: "createdAt"},
+		{Type: "Query", Member: "search"},
+		{Type: "User", Member: "email"},
+	}, UnusedSchemaFields(schema, docs))
+}
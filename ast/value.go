@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -53,9 +54,18 @@ func (v *Value) Value(vars map[string]interface{}) (interface{}, error) {
 		}
 		return nil, nil
 	case IntValue:
-		return strconv.ParseInt(v.Raw, 10, 64)
+		i, err := strconv.ParseInt(v.Raw, 10, 64)
+		if err != nil {
+			return nil, numberError(v.Raw, err)
+		}
+		return i, nil
 	case FloatValue:
-		return strconv.ParseFloat(v.Raw, 64)
+		f, err := strconv.ParseFloat(v.Raw, 64)
+		if err != nil {
+			// ParseFloat returns ±Inf when out of range, don't let that leak out
+			return nil, numberError(v.Raw, err)
+		}
+		return f, nil
 	case StringValue, BlockValue, EnumValue:
 		return v.Raw, nil
 	case BooleanValue:
@@ -87,6 +97,13 @@ func (v *Value) Value(vars map[string]interface{}) (interface{}, error) {
 	}
 }
 
+func numberError(raw string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("value out of range: %s", raw)
+	}
+	return err
+}
+
 func (v *Value) String() string {
 	if v == nil {
 		return "<nil>"
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueOutOfRange(t *testing.T) {
+	for _, tc := range []struct {
+		kind ValueKind
+		raw  string
+	}{
+		{FloatValue, "1e999999999"},
+		{FloatValue, "-1e999999999"},
+		{IntValue, "9223372036854775808"},
+		{IntValue, "111111111111111111111111111111111111111111111111111111111111"},
+	} {
+		val, err := (&Value{Kind: tc.kind, Raw: tc.raw}).Value(nil)
+		require.EqualError(t, err, "value out of range: "+tc.raw)
+		require.Nil(t, val)
+	}
+
+	val, err := (&Value{Kind: IntValue, Raw: "-0"}).Value(nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), val)
+
+	val, err = (&Value{Kind: FloatValue, Raw: "1e-999999999"}).Value(nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), val)
+}
@@ -15,15 +15,83 @@ type Formatter interface {
 	FormatQueryDocument(doc *ast.QueryDocument)
 }
 
-func NewFormatter(w io.Writer) Formatter {
-	return &formatter{writer: w}
+type FormatterOption func(*formatter)
+
+// WithStableOrdering emits a diff friendly layout that doesn't depend on the order of the input
+// sources. FormatSchema emits the root operation types first, followed by all other types sorted by
+// name. Fields and enum values keep their source order, ordered by source name and then offset,
+// and interfaces and union members are sorted by name.
+func WithStableOrdering() FormatterOption {
+	return func(f *formatter) {
+		f.stableOrder = true
+	}
+}
+
+// WithSortedFields is WithStableOrdering but fields, enum values and the arguments given to directives are sorted
+// by name too.
+func WithSortedFields() FormatterOption {
+	return func(f *formatter) {
+		f.stableOrder = true
+		f.sortFields = true
+	}
+}
+
+// WithSortedInterfaces sorts the interfaces listed after implements by name. Otherwise they are emitted in the
+// order they appear in the AST, which is source order with extensions appended.
+func WithSortedInterfaces() FormatterOption {
+	return func(f *formatter) {
+		f.sortInterfaces = true
+	}
+}
+
+// WithBuiltin emits the built in scalars, introspection types and directives from the prelude. They are left out
+// by default, using the BuiltIn flag the schema loader sets on them, so formatting a loaded schema only emits the
+// definitions from its own sources.
+func WithBuiltin() FormatterOption {
+	return func(f *formatter) {
+		f.emitBuiltin = true
+	}
+}
+
+// WithCanonicalLayout groups definitions by kind, as reference documentation usually does: the schema definition,
+// directives sorted by name, the root operation types, then scalars, enums, input objects, interfaces, unions and
+// objects, each sorted by name. Extensions of a schema document follow in the same order.
+func WithCanonicalLayout() FormatterOption {
+	return func(f *formatter) {
+		f.canonical = true
+	}
+}
+
+// WithBlankLines separates top level definitions with an empty line, and keeps the empty lines the author put
+// between groups of fields and enum values, as recorded by the parser in LeadingBlankLine. Fields and enum values
+// sorted by WithSortedFields lose their grouping.
+func WithBlankLines() FormatterOption {
+	return func(f *formatter) {
+		f.blankLines = true
+	}
+}
+
+func NewFormatter(w io.Writer, options ...FormatterOption) Formatter {
+	f := &formatter{writer: w}
+	for _, opt := range options {
+		opt(f)
+	}
+	return f
 }
 
 type formatter struct {
 	writer io.Writer
 
-	indent      int
-	emitBuiltin bool
+	indent         int
+	emitBuiltin    bool
+	stableOrder    bool
+	sortFields     bool
+	sortInterfaces bool
+	blankLines     bool
+	canonical      bool
+
+	// whether a top level definition has been written, for WithBlankLines
+	wroteDefinition bool
 
 	padNext  bool
 	lineHead bool
@@ -77,15 +145,27 @@ func (f *formatter) WriteString(s string) *formatter {
 	return f
 }
 
+// WriteDescription writes single line descriptions as a quoted string, and multi line descriptions as a
+// block string when the block string would produce the same value. The lines of a block string are indented to
+// the current level, which the common indent removal undoes when it's parsed.
 func (f *formatter) WriteDescription(s string) *formatter {
 	if s == "" {
 		return f
 	}
 
+	if !strings.Contains(s, "\n") || !isBlockStringSafe(s) {
+		f.WriteString(quoteString(s)).WriteNewline()
+		return f
+	}
+
 	f.WriteString(`"""`).WriteNewline()
 
-	ss := strings.Split(s, "\n")
+	ss := strings.Split(strings.Replace(s, `"""`, `\"""`, -1), "\n")
 	for _, s := range ss {
+		if s == "" {
+			f.WriteNewline()
+			continue
+		}
 		f.WriteString(s).WriteNewline()
 	}
 
@@ -94,6 +174,75 @@ func (f *formatter) WriteDescription(s string) *formatter {
 	return f
 }
 
+// isBlockStringSafe reports whether s survives the block string value dedent and trim unchanged.
+func isBlockStringSafe(s string) bool {
+	for _, r := range s {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+
+	lines := strings.Split(s, "\n")
+	if strings.TrimLeft(lines[0], " \t") == "" || strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		return false
+	}
+
+	for _, line := range lines {
+		if line != "" && line[0] != ' ' && line[0] != '\t' {
+			return true
+		}
+	}
+	// every line is indented, the common indent would be removed
+	return false
+}
+
+// quoteString returns s as a GraphQL string literal.
+func quoteString(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// startDefinition writes the empty line between top level definitions WithBlankLines.
+func (f *formatter) startDefinition() {
+	if f.blankLines && f.wroteDefinition {
+		f.WriteNewline()
+	}
+	f.wroteDefinition = true
+}
+
+// writeGroupSeparator keeps the empty line the author put before a field or enum value WithBlankLines.
+func (f *formatter) writeGroupSeparator(first, leadingBlankLine bool) {
+	if f.blankLines && !f.sortFields && !first && leadingBlankLine {
+		f.WriteNewline()
+	}
+}
+
 func (f *formatter) IncrementIndent() {
 	f.indent++
 }
@@ -123,22 +272,32 @@ func (f *formatter) FormatSchema(schema *ast.Schema) {
 	startSchema := func() {
 		if !inSchema {
 			inSchema = true
+			f.startDefinition()
 
-			f.WriteWord("schema").WriteString("{").WriteNewline()
+			f.WriteDescription(schema.Description)
+			f.WriteWord("schema")
+			f.FormatDirectiveList(schema.SchemaDirectives)
+			f.WriteString("{").WriteNewline()
 			f.IncrementIndent()
 		}
 	}
-	if schema.Query != nil && schema.Query.Name != "Query" {
+	// a schema definition replaces the default root names, so once one is needed, for directives or a root with
+	// another name, it has to spell out every root, even the default ones
+	explicit := schema.Description != "" || len(schema.SchemaDirectives) != 0 ||
+		(schema.Query != nil && schema.Query.Name != "Query") ||
+		(schema.Mutation != nil && schema.Mutation.Name != "Mutation") ||
+		(schema.Subscription != nil && schema.Subscription.Name != "Subscription")
+	if schema.Query != nil && explicit {
 		startSchema()
 		f.WriteWord("query").NoPadding().WriteString(":").NeedPadding()
 		f.WriteWord(schema.Query.Name).WriteNewline()
 	}
-	if schema.Mutation != nil && schema.Mutation.Name != "Mutation" {
+	if schema.Mutation != nil && explicit {
 		startSchema()
 		f.WriteWord("mutation").NoPadding().WriteString(":").NeedPadding()
 		f.WriteWord(schema.Mutation.Name).WriteNewline()
 	}
-	if schema.Subscription != nil && schema.Subscription.Name != "Subscription" {
+	if schema.Subscription != nil && explicit {
 		startSchema()
 		f.WriteWord("subscription").NoPadding().WriteString(":").NeedPadding()
 		f.WriteWord(schema.Subscription.Name).WriteNewline()
@@ -157,12 +316,43 @@ func (f *formatter) FormatSchema(schema *ast.Schema) {
 		f.FormatDirectiveDefinition(schema.Directives[name])
 	}
 
+	if f.canonical {
+		defs := make(ast.DefinitionList, 0, len(schema.Types))
+		for _, def := range schema.Types {
+			defs = append(defs, def)
+		}
+		var roots []string
+		for _, def := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+			if def != nil {
+				roots = append(roots, def.Name)
+			}
+		}
+		f.FormatDefinitionList(canonicalOrder(defs, roots), false)
+		return
+	}
+
+	var roots []*ast.Definition
+	if f.stableOrder {
+		for _, def := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+			if def != nil {
+				roots = append(roots, def)
+				f.FormatDefinition(def, false)
+			}
+		}
+	}
+
 	typeNames := make([]string, 0, len(schema.Types))
 	for name := range schema.Types {
 		typeNames = append(typeNames, name)
 	}
 	sort.Strings(typeNames)
+types:
 	for _, name := range typeNames {
+		for _, root := range roots {
+			if root.Name == name {
+				continue types
+			}
+		}
 		f.FormatDefinition(schema.Types[name], false)
 	}
 }
@@ -177,12 +367,78 @@ func (f *formatter) FormatSchemaDocument(doc *ast.SchemaDocument) {
 	f.FormatSchemaDefinitionList(doc.Schema, false)
 	f.FormatSchemaDefinitionList(doc.SchemaExtension, true)
 
+	if f.canonical {
+		roots := documentRoots(doc)
+		f.FormatDirectiveDefinitionList(sortedDirectives(doc.Directives))
+		f.FormatDefinitionList(canonicalOrder(doc.Definitions, roots), false)
+		f.FormatDefinitionList(canonicalOrder(doc.Extensions, roots), true)
+		return
+	}
+
 	f.FormatDirectiveDefinitionList(doc.Directives)
 
 	f.FormatDefinitionList(doc.Definitions, false)
 	f.FormatDefinitionList(doc.Extensions, true)
 }
 
+// canonicalKinds is the order WithCanonicalLayout groups types in.
+var canonicalKinds = map[ast.DefinitionKind]int{
+	ast.Scalar:      0,
+	ast.Enum:        1,
+	ast.InputObject: 2,
+	ast.Interface:   3,
+	ast.Union:       4,
+	ast.Object:      5,
+}
+
+// canonicalOrder sorts definitions for WithCanonicalLayout, the root operation types named by roots first, in
+// that order, then every other type by kind and name.
+func canonicalOrder(defs ast.DefinitionList, roots []string) ast.DefinitionList {
+	rootIndex := func(def *ast.Definition) int {
+		for i, root := range roots {
+			if def.Name == root {
+				return i
+			}
+		}
+		return len(roots)
+	}
+
+	sorted := append(ast.DefinitionList{}, defs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ra, rb := rootIndex(a), rootIndex(b); ra != rb {
+			return ra < rb
+		}
+		if ka, kb := canonicalKinds[a.Kind], canonicalKinds[b.Kind]; ka != kb {
+			return ka < kb
+		}
+		return a.Name < b.Name
+	})
+	return sorted
+}
+
+// documentRoots returns the names of the root operation types of a schema document, the default names unless a
+// schema definition or extension says otherwise.
+func documentRoots(doc *ast.SchemaDocument) []string {
+	roots := map[ast.Operation]string{ast.Query: "Query", ast.Mutation: "Mutation", ast.Subscription: "Subscription"}
+	for _, list := range []ast.SchemaDefinitionList{doc.Schema, doc.SchemaExtension} {
+		for _, def := range list {
+			for _, op := range def.OperationTypes {
+				roots[op.Operation] = op.Type
+			}
+		}
+	}
+	return []string{roots[ast.Query], roots[ast.Mutation], roots[ast.Subscription]}
+}
+
+func sortedDirectives(list ast.DirectiveDefinitionList) ast.DirectiveDefinitionList {
+	sorted := append(ast.DirectiveDefinitionList{}, list...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
 func (f *formatter) FormatQueryDocument(doc *ast.QueryDocument) {
 	// TODO emit by position based order
 
@@ -199,10 +455,29 @@ func (f *formatter) FormatSchemaDefinitionList(lists ast.SchemaDefinitionList, e
 		return
 	}
 
+	f.startDefinition()
+
+	if !extension {
+		for _, def := range lists {
+			f.WriteDescription(def.Description)
+		}
+	}
+
 	if extension {
 		f.WriteWord("extend")
 	}
-	f.WriteWord("schema").WriteString("{").WriteNewline()
+	f.WriteWord("schema")
+	var hasOperationTypes bool
+	for _, def := range lists {
+		f.FormatDirectiveList(def.Directives)
+		hasOperationTypes = hasOperationTypes || len(def.OperationTypes) != 0
+	}
+	if !hasOperationTypes {
+		// eg `extend schema @link(url: "...")`
+		f.WriteNewline()
+		return
+	}
+	f.WriteString("{").WriteNewline()
 	f.IncrementIndent()
 
 	for _, def := range lists {
@@ -213,11 +488,9 @@ func (f *formatter) FormatSchemaDefinitionList(lists ast.SchemaDefinitionList, e
 	f.WriteString("}").WriteNewline()
 }
 
+// FormatSchemaDefinition writes the operation types of a schema definition. Its description and directives
+// belong before the opening brace, so they are written by FormatSchemaDefinitionList.
 func (f *formatter) FormatSchemaDefinition(def *ast.SchemaDefinition) {
-	f.WriteDescription(def.Description)
-
-	f.FormatDirectiveList(def.Directives)
-
 	f.FormatOperationTypeDefinitionList(def.OperationTypes)
 }
 
@@ -241,7 +514,13 @@ func (f *formatter) FormatFieldList(fieldList ast.FieldList) {
 	f.WriteString("{").WriteNewline()
 	f.IncrementIndent()
 
+	first := true
 	for _, field := range fieldList {
+		if !f.emitBuiltin && strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		f.writeGroupSeparator(first, field.LeadingBlankLine)
+		first = false
 		f.FormatFieldDefinition(field)
 	}
 
@@ -276,22 +555,36 @@ func (f *formatter) FormatArgumentDefinitionList(lists ast.ArgumentDefinitionLis
 		return
 	}
 
+	// descriptions need their own line, so put every argument on its own line when any has one
+	multiline := false
+	for _, arg := range lists {
+		if arg.Description != "" {
+			multiline = true
+			break
+		}
+	}
+
 	f.WriteString("(")
+	if multiline {
+		f.WriteNewline().IncrementIndent()
+	}
 	for idx, arg := range lists {
 		f.FormatArgumentDefinition(arg)
 
-		if idx != len(lists)-1 {
+		if multiline {
+			f.WriteNewline()
+		} else if idx != len(lists)-1 {
 			f.NoPadding().WriteWord(",")
 		}
 	}
+	if multiline {
+		f.DecrementIndent()
+	}
 	f.NoPadding().WriteString(")").NeedPadding()
 }
 
 func (f *formatter) FormatArgumentDefinition(def *ast.ArgumentDefinition) {
-	if def.Description != "" {
-		f.WriteNewline().IncrementIndent()
-		f.WriteDescription(def.Description)
-	}
+	f.WriteDescription(def.Description)
 
 	f.WriteWord(def.Name).NoPadding().WriteString(":").NeedPadding()
 	f.FormatType(def.Type)
@@ -301,10 +594,7 @@ func (f *formatter) FormatArgumentDefinition(def *ast.ArgumentDefinition) {
 		f.FormatValue(def.DefaultValue)
 	}
 
-	if def.Description != "" {
-		f.DecrementIndent()
-		f.WriteNewline()
-	}
+	f.NeedPadding().FormatDirectiveList(def.Directives)
 }
 
 func (f *formatter) FormatDirectiveLocation(location ast.DirectiveLocation) {
@@ -322,12 +612,11 @@ func (f *formatter) FormatDirectiveDefinitionList(lists ast.DirectiveDefinitionL
 }
 
 func (f *formatter) FormatDirectiveDefinition(def *ast.DirectiveDefinition) {
-	if !f.emitBuiltin {
-		if def.Position.Src.BuiltIn {
-			return
-		}
+	if !f.emitBuiltin && def.BuiltIn {
+		return
 	}
 
+	f.startDefinition()
 	f.WriteDescription(def.Description)
 	f.WriteWord("directive").WriteString("@").WriteWord(def.Name)
 
@@ -336,6 +625,10 @@ func (f *formatter) FormatDirectiveDefinition(def *ast.DirectiveDefinition) {
 		f.FormatArgumentDefinitionList(def.Arguments)
 	}
 
+	if def.IsRepeatable {
+		f.WriteWord("repeatable")
+	}
+
 	if len(def.Locations) != 0 {
 		f.WriteWord("on")
 
@@ -366,6 +659,7 @@ func (f *formatter) FormatDefinition(def *ast.Definition, extend bool) {
 		return
 	}
 
+	f.startDefinition()
 	f.WriteDescription(def.Description)
 
 	if extend {
@@ -393,22 +687,74 @@ func (f *formatter) FormatDefinition(def *ast.Definition, extend bool) {
 	}
 
 	if len(def.Interfaces) != 0 {
-		f.WriteWord("implements").WriteWord(strings.Join(def.Interfaces, " & "))
+		f.WriteWord("implements").WriteWord(strings.Join(sortedNames(def.Interfaces, f.stableOrder || f.sortInterfaces), " & "))
 	}
 
 	f.FormatDirectiveList(def.Directives)
 
 	if len(def.Types) != 0 {
-		f.WriteWord("=").WriteWord(strings.Join(def.Types, " | "))
+		f.WriteWord("=").WriteWord(strings.Join(sortedNames(def.Types, f.stableOrder), " | "))
 	}
 
-	f.FormatFieldList(def.Fields)
+	f.FormatFieldList(f.orderedFields(def.Fields))
 
-	f.FormatEnumValueList(def.EnumValues)
+	f.FormatEnumValueList(f.orderedEnumValues(def.EnumValues))
 
 	f.WriteNewline()
 }
 
+// sortedNames returns names sorted if sorted is true, and unchanged otherwise.
+func sortedNames(names []string, sorted bool) []string {
+	if !sorted {
+		return names
+	}
+	names = append([]string{}, names...)
+	sort.Strings(names)
+	return names
+}
+
+func (f *formatter) orderedFields(fields ast.FieldList) ast.FieldList {
+	if !f.stableOrder {
+		return fields
+	}
+	sorted := append(ast.FieldList{}, fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if f.sortFields {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return positionLess(sorted[i].Position, sorted[j].Position)
+	})
+	return sorted
+}
+
+func (f *formatter) orderedEnumValues(values ast.EnumValueList) ast.EnumValueList {
+	if !f.stableOrder {
+		return values
+	}
+	sorted := append(ast.EnumValueList{}, values...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if f.sortFields {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return positionLess(sorted[i].Position, sorted[j].Position)
+	})
+	return sorted
+}
+
+// positionLess orders positions by source name and then offset, positions without a source go last.
+func positionLess(a, b *ast.Position) bool {
+	if a == nil || a.Src == nil {
+		return false
+	}
+	if b == nil || b.Src == nil {
+		return true
+	}
+	if a.Src.Name != b.Src.Name {
+		return a.Src.Name < b.Src.Name
+	}
+	return a.Start < b.Start
+}
+
 func (f *formatter) FormatEnumValueList(lists ast.EnumValueList) {
 	if len(lists) == 0 {
 		return
@@ -417,7 +763,8 @@ func (f *formatter) FormatEnumValueList(lists ast.EnumValueList) {
 	f.WriteString("{").WriteNewline()
 	f.IncrementIndent()
 
-	for _, v := range lists {
+	for i, v := range lists {
+		f.writeGroupSeparator(i == 0, v.LeadingBlankLine)
 		f.FormatEnumValueDefinition(v)
 	}
 
@@ -441,6 +788,7 @@ func (f *formatter) FormatOperationList(lists ast.OperationList) {
 }
 
 func (f *formatter) FormatOperationDefinition(def *ast.OperationDefinition) {
+	f.startDefinition()
 	f.WriteWord(string(def.Operation))
 	if def.Name != "" {
 		f.WriteWord(def.Name)
@@ -466,7 +814,14 @@ func (f *formatter) FormatDirectiveList(lists ast.DirectiveList) {
 
 func (f *formatter) FormatDirective(dir *ast.Directive) {
 	f.WriteString("@").WriteWord(dir.Name)
-	f.FormatArgumentList(dir.Arguments)
+	args := dir.Arguments
+	if f.sortFields && len(args) > 1 {
+		args = append(ast.ArgumentList{}, args...)
+		sort.SliceStable(args, func(i, j int) bool {
+			return args[i].Name < args[j].Name
+		})
+	}
+	f.FormatArgumentList(args)
 }
 
 func (f *formatter) FormatArgumentList(lists ast.ArgumentList) {
@@ -496,6 +851,7 @@ func (f *formatter) FormatFragmentDefinitionList(lists ast.FragmentDefinitionLis
 }
 
 func (f *formatter) FormatFragmentDefinition(def *ast.FragmentDefinition) {
+	f.startDefinition()
 	f.WriteWord("fragment").WriteWord(def.Name)
 	f.FormatVariableDefinitionList(def.VariableDefinition)
 	f.WriteWord("on").WriteWord(def.TypeCondition)
@@ -532,8 +888,7 @@ func (f *formatter) FormatVariableDefinition(def *ast.VariableDefinition) {
 		f.FormatValue(def.DefaultValue)
 	}
 
-	// TODO https://github.com/dgraph-io/gqlparser/v2/issues/102
-	//   VariableDefinition : Variable : Type DefaultValue? Directives[Const]?
+	f.FormatDirectiveList(def.Directives)
 }
 
 func (f *formatter) FormatSelectionSet(sets ast.SelectionSet) {
@@ -609,5 +964,5 @@ func (f *formatter) FormatType(t *ast.Type) {
 }
 
 func (f *formatter) FormatValue(value *ast.Value) {
-	f.WriteString(value.String())
+	f.WriteString(value.String()).NeedPadding()
 }
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"unicode/utf8"
 
@@ -132,6 +133,256 @@ func TestFormatter_FormatQueryDocument(t *testing.T) {
 	})
 }
 
+func TestFormatter_StableOrdering(t *testing.T) {
+	a := &ast.Source{Name: "a.graphql", Input: `
+type Query {
+	users: [User!]!
+}
+type User implements Node {
+	name: String
+	id: ID!
+}
+interface Node {
+	id: ID!
+}
+enum Role {
+	ADMIN
+}
+`}
+	b := &ast.Source{Name: "b.graphql", Input: `
+type Account implements Node {
+	id: ID!
+}
+extend type User implements Named {
+	email: String
+}
+interface Named {
+	name: String
+}
+extend enum Role {
+	GUEST
+}
+extend type Query {
+	accounts: [Account!]!
+}
+`}
+
+	format := func(opt formatter.FormatterOption, sources ...*ast.Source) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, opt).FormatSchema(gqlparser.MustLoadSchema(sources...))
+		return buf.String()
+	}
+
+	t.Run("source order", func(t *testing.T) {
+		expected := `type Query {
+	users: [User!]!
+	accounts: [Account!]!
+}
+type Account implements Node {
+	id: ID!
+}
+interface Named {
+	name: String
+}
+interface Node {
+	id: ID!
+}
+enum Role {
+	ADMIN
+	GUEST
+}
+type User implements Named & Node {
+	name: String
+	id: ID!
+	email: String
+}
+`
+		assert.Equal(t, expected, format(formatter.WithStableOrdering(), a, b))
+		assert.Equal(t, expected, format(formatter.WithStableOrdering(), b, a))
+	})
+
+	t.Run("sorted fields", func(t *testing.T) {
+		expected := `type Query {
+	accounts: [Account!]!
+	users: [User!]!
+}
+type Account implements Node {
+	id: ID!
+}
+interface Named {
+	name: String
+}
+interface Node {
+	id: ID!
+}
+enum Role {
+	ADMIN
+	GUEST
+}
+type User implements Named & Node {
+	email: String
+	id: ID!
+	name: String
+}
+`
+		assert.Equal(t, expected, format(formatter.WithSortedFields(), a, b))
+		assert.Equal(t, expected, format(formatter.WithSortedFields(), b, a))
+	})
+}
+
+func TestFormatter_InterfaceOrder(t *testing.T) {
+	src := &ast.Source{Name: "schema.graphql", Input: `interface Zed { id: ID }
+interface Alpha { id: ID }
+interface Mid { id: ID }
+type Thing implements Zed & Alpha & Mid { id: ID }
+type Query { thing: Thing }
+`}
+
+	format := func(options ...formatter.FormatterOption) string {
+		doc, err := parser.ParseSchema(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, options...).FormatSchemaDocument(doc)
+		return buf.String()
+	}
+
+	assert.Contains(t, format(), "type Thing implements Zed & Alpha & Mid {")
+	assert.Contains(t, format(formatter.WithSortedInterfaces()), "type Thing implements Alpha & Mid & Zed {")
+}
+
+func TestFormatter_DuplicateDirectives(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+directive @tag(name: String!) repeatable on OBJECT | FIELD_DEFINITION
+directive @key on OBJECT
+type Query @tag(name: "a") @key @tag(name: "b") {
+	field: Int @tag(name: "x") @deprecated @tag(name: "y")
+}
+`})
+
+	query := schema.Types["Query"]
+	assert.Len(t, query.Directives, 3)
+	tags := query.Directives.ForNames("tag")
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "a", tags[0].Arguments.ForName("name").Value.Raw)
+	assert.Equal(t, "b", tags[1].Arguments.ForName("name").Value.Raw)
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(schema)
+	assert.Contains(t, buf.String(), `type Query @tag(name: "a") @key @tag(name: "b") {`)
+	assert.Contains(t, buf.String(), `field: Int @tag(name: "x") @deprecated @tag(name: "y")`)
+}
+
+func TestFormatter_DirectiveArguments(t *testing.T) {
+	input := `directive @constraint(min: Int, max: Int, pattern: String) on FIELD_DEFINITION | ARGUMENT_DEFINITION
+type Query {
+	count(limit: Int @constraint(pattern: "\\d+", max: 10, min: 1)): Int @constraint(min: 1, max: 10)
+}
+`
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: input})
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(schema)
+	assert.Contains(t, buf.String(), `count(limit: Int @constraint(pattern: "\\d+", max: 10, min: 1)): Int @constraint(min: 1, max: 10)`)
+
+	// the output loads back to the same arguments in the same order
+	again := gqlparser.MustLoadSchema(&ast.Source{Name: "formatted.graphql", Input: buf.String()})
+	assert.True(t, ast.Equal(schema.Types["Query"], again.Types["Query"]), ast.Mismatch(schema.Types["Query"], again.Types["Query"]))
+
+	buf.Reset()
+	formatter.NewFormatter(&buf, formatter.WithSortedFields()).FormatSchema(schema)
+	assert.Contains(t, buf.String(), `count(limit: Int @constraint(max: 10, min: 1, pattern: "\\d+")): Int @constraint(max: 10, min: 1)`)
+	limit := schema.Query.Fields.ForName("count").Arguments.ForName("limit")
+	assert.Equal(t, "pattern", limit.Directives[0].Arguments[0].Name, "sorting must not change the schema")
+}
+
+func TestFormatter_DescriptionRoundTrip(t *testing.T) {
+	descriptions := []string{
+		"single line",
+		`with "quotes" and a \ backslash`,
+		"multi\nline",
+		"multi\n  indented\n\nwith a blank line",
+		"embedded \"\"\" triple quotes\nand a second line",
+		"\nleading blank line",
+		"trailing blank line\n",
+		"  indented\n  on every line",
+		"control \u0001 character\nand a second line",
+	}
+
+	for _, desc := range descriptions {
+		doc := &ast.SchemaDocument{Definitions: ast.DefinitionList{{
+			Kind:        ast.Scalar,
+			Name:        "Foo",
+			Description: desc,
+		}}}
+
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf).FormatSchemaDocument(doc)
+
+		parsed, err := parser.ParseSchema(&ast.Source{Input: buf.String()})
+		if !assert.Nil(t, err, buf.String()) {
+			continue
+		}
+		assert.Equal(t, desc, parsed.Definitions[0].Description, buf.String())
+	}
+}
+
+func TestFormatter_NestedDescriptionRoundTrip(t *testing.T) {
+	descriptions := []string{
+		"single line",
+		"multi\nline",
+		"multi\n  indented\n\nwith a blank line",
+		"embedded \"\"\" triple quotes\nand a second line",
+		"\nleading blank line",
+		"trailing blank line\n",
+		"  indented\n  on every line",
+	}
+
+	for _, desc := range descriptions {
+		// block strings nested in a field and its argument are indented with them, which the dedent has to undo
+		doc := &ast.SchemaDocument{Definitions: ast.DefinitionList{{
+			Kind:        ast.Object,
+			Name:        "Foo",
+			Description: desc,
+			Fields: ast.FieldList{{
+				Name:        "bar",
+				Description: desc,
+				Type:        ast.NamedType("Int", nil),
+				Arguments: ast.ArgumentDefinitionList{{
+					Name:        "baz",
+					Description: desc,
+					Type:        ast.NamedType("Int", nil),
+				}},
+			}},
+		}}}
+
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf).FormatSchemaDocument(doc)
+
+		parsed, err := parser.ParseSchema(&ast.Source{Input: buf.String()})
+		if !assert.Nil(t, err, buf.String()) {
+			continue
+		}
+		def := parsed.Definitions[0]
+		assert.Equal(t, desc, def.Description, buf.String())
+		assert.Equal(t, desc, def.Fields[0].Description, buf.String())
+		assert.Equal(t, desc, def.Fields[0].Arguments[0].Description, buf.String())
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchemaDocument(&ast.SchemaDocument{Definitions: ast.DefinitionList{{
+		Kind: ast.Object,
+		Name: "Foo",
+		Fields: ast.FieldList{{
+			Name:        "bar",
+			Description: "multi\n  indented\n\nwith a blank line",
+			Type:        ast.NamedType("Int", nil),
+		}},
+	}}})
+	assert.Equal(t, "type Foo {\n\t\"\"\"\n\tmulti\n\t  indented\n\n\twith a blank line\n\t\"\"\"\n\tbar: Int\n}\n", buf.String())
+}
+
 type goldenConfig struct {
 	SourceDir        string
 	IsTarget         func(f os.FileInfo) bool
@@ -212,3 +463,199 @@ func mustReadFile(name string) string {
 
 	return string(src)
 }
+
+func TestSignatures(t *testing.T) {
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema", Input: `
+		enum DateFormat { ISO UNIX }
+		interface Node { id: ID! }
+		type User implements Node {
+			id: ID!
+			email(format: DateFormat = ISO, limit: Int = 10): String!
+			friends: [User!]
+		}
+		union SearchResult = User
+		input Filter { name: String = "bob" }
+		type Query { user: User }
+	`})
+	if gqlErr != nil {
+		t.Fatal(gqlErr)
+	}
+
+	user := schema.Types["User"]
+	assert.Equal(t, "email(format: DateFormat = ISO, limit: Int = 10): String!", formatter.FieldSignature(user.Fields.ForName("email")))
+	assert.Equal(t, "friends: [User!]", formatter.FieldSignature(user.Fields.ForName("friends")))
+	assert.Equal(t, `name: String = "bob"`, formatter.FieldSignature(schema.Types["Filter"].Fields.ForName("name")))
+
+	assert.Equal(t, "type User implements Node", formatter.TypeSignature(user))
+	assert.Equal(t, "union SearchResult = User", formatter.TypeSignature(schema.Types["SearchResult"]))
+	assert.Equal(t, "enum DateFormat", formatter.TypeSignature(schema.Types["DateFormat"]))
+	assert.Equal(t, "input Filter", formatter.TypeSignature(schema.Types["Filter"]))
+}
+
+func TestFormatter_Builtin(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+directive @tag(name: String!) on OBJECT
+type Query @tag(name: "a") {
+	name: String
+}
+`})
+
+	format := func(options ...formatter.FormatterOption) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, options...).FormatSchema(schema)
+		return buf.String()
+	}
+
+	assert.Equal(t, `directive @tag(name: String!) on OBJECT
+type Query @tag(name: "a") {
+	name: String
+}
+`, format())
+
+	withBuiltin := format(formatter.WithBuiltin())
+	assert.Contains(t, withBuiltin, "directive @tag(name: String!) on OBJECT\n")
+	assert.Contains(t, withBuiltin, "directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT\n")
+	assert.Contains(t, withBuiltin, "directive @deprecated(")
+	assert.Contains(t, withBuiltin, "scalar Int\n")
+	assert.Contains(t, withBuiltin, "scalar String\n")
+	assert.Contains(t, withBuiltin, "type __Schema {")
+}
+
+func TestFormatter_BlankLines(t *testing.T) {
+	doc, err := parser.ParseSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query {
+	user: User
+	users: [User!]!
+
+	# search
+	search(text: String!): [User!]!
+}
+type User {
+	id: ID!
+
+	name: String
+}
+enum Role {
+	ADMIN
+	OWNER
+
+
+	GUEST
+}
+`})
+	assert.Nil(t, err)
+
+	format := func(options ...formatter.FormatterOption) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, options...).FormatSchemaDocument(doc)
+		return buf.String()
+	}
+
+	assert.Equal(t, `type Query {
+	user: User
+	users: [User!]!
+	search(text: String!): [User!]!
+}
+type User {
+	id: ID!
+	name: String
+}
+enum Role {
+	ADMIN
+	OWNER
+	GUEST
+}
+`, format())
+
+	assert.Equal(t, `type Query {
+	user: User
+	users: [User!]!
+
+	search(text: String!): [User!]!
+}
+
+type User {
+	id: ID!
+
+	name: String
+}
+
+enum Role {
+	ADMIN
+	OWNER
+
+	GUEST
+}
+`, format(formatter.WithBlankLines()))
+
+	query, err := parser.ParseQuery(&ast.Source{Input: `query A { a } query B { b } fragment F on Query { c }`})
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithBlankLines()).FormatQueryDocument(query)
+	assert.Equal(t, "query A {\n\ta\n}\n\nquery B {\n\tb\n}\n\nfragment F on Query {\n\tc\n}\n", buf.String())
+}
+
+func TestFormatter_CanonicalLayout(t *testing.T) {
+	input := `
+type User implements Node { id: ID! role: Role created: Time }
+union SearchResult = User | Post
+type Mutation { ping: Boolean }
+directive @tag on OBJECT | SCALAR
+input Filter { role: Role }
+type Root { search(filter: Filter): [SearchResult!]! }
+interface Node { id: ID! }
+scalar Time
+type Post implements Node { id: ID! }
+enum Role { ADMIN GUEST }
+directive @auth on FIELD_DEFINITION
+schema { query: Root mutation: Mutation }
+extend type User @tag
+extend scalar Time @tag
+`
+	expected := `schema {
+	query: Root
+	mutation: Mutation
+}
+directive @auth on FIELD_DEFINITION
+directive @tag on OBJECT | SCALAR
+type Root {
+	search(filter: Filter): [SearchResult!]!
+}
+type Mutation {
+	ping: Boolean
+}
+scalar Time
+enum Role {
+	ADMIN
+	GUEST
+}
+input Filter {
+	role: Role
+}
+interface Node {
+	id: ID!
+}
+union SearchResult = User | Post
+type Post implements Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	role: Role
+	created: Time
+}
+`
+
+	doc, err := parser.ParseSchema(&ast.Source{Name: "schema.graphql", Input: input})
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithCanonicalLayout()).FormatSchemaDocument(doc)
+	assert.Equal(t, expected+"extend scalar Time @tag\nextend type User @tag\n", buf.String())
+
+	_, err = gqlparser.LoadSchema(&ast.Source{Name: "formatted.graphql", Input: buf.String()})
+	assert.Nil(t, err)
+
+	buf.Reset()
+	formatter.NewFormatter(&buf, formatter.WithCanonicalLayout()).FormatSchema(gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: input}))
+	assert.Equal(t, strings.Replace(strings.Replace(expected, "scalar Time\n", "scalar Time @tag\n", 1), "type User implements Node {", "type User implements Node @tag {", 1), buf.String())
+}
@@ -0,0 +1,67 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+)
+
+// FieldSignature renders a field on a single line, with its arguments and their defaults,
+// eg `email(format: DateFormat = ISO): String!`.
+func FieldSignature(f *ast.FieldDefinition) string {
+	var sb strings.Builder
+	sb.WriteString(f.Name)
+	if len(f.Arguments) != 0 {
+		sb.WriteString("(")
+		for i, arg := range f.Arguments {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(arg.Name)
+			sb.WriteString(": ")
+			sb.WriteString(arg.Type.String())
+			if arg.DefaultValue != nil {
+				sb.WriteString(" = ")
+				sb.WriteString(arg.DefaultValue.String())
+			}
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString(": ")
+	sb.WriteString(f.Type.String())
+	if f.DefaultValue != nil {
+		sb.WriteString(" = ")
+		sb.WriteString(f.DefaultValue.String())
+	}
+	return sb.String()
+}
+
+// TypeSignature renders the header of a type definition on a single line, eg `type User implements Node & Entity`
+// or `union SearchResult = User | Post`.
+func TypeSignature(d *ast.Definition) string {
+	var sb strings.Builder
+	switch d.Kind {
+	case ast.Scalar:
+		sb.WriteString("scalar ")
+	case ast.Object:
+		sb.WriteString("type ")
+	case ast.Interface:
+		sb.WriteString("interface ")
+	case ast.Union:
+		sb.WriteString("union ")
+	case ast.Enum:
+		sb.WriteString("enum ")
+	case ast.InputObject:
+		sb.WriteString("input ")
+	}
+	sb.WriteString(d.Name)
+	if len(d.Interfaces) != 0 {
+		sb.WriteString(" implements ")
+		sb.WriteString(strings.Join(d.Interfaces, " & "))
+	}
+	if len(d.Types) != 0 {
+		sb.WriteString(" = ")
+		sb.WriteString(strings.Join(d.Types, " | "))
+	}
+	return sb.String()
+}
@@ -2,7 +2,9 @@ package gqlerror
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
@@ -29,9 +31,32 @@ func (err *Error) SetFile(file string) {
 	err.Extensions["file"] = file
 }
 
+// MapSource rewrites the locations in src, a Source built by ast.ConcatSources, to the original file and line
+// they came from, and points the file extension at the file of the first location.
+func (err *Error) MapSource(src *ast.Source) {
+	if src == nil || len(src.Segments) == 0 {
+		return
+	}
+	for i, loc := range err.Locations {
+		if loc.Source != src.Name {
+			continue
+		}
+		err.Locations[i].Source, err.Locations[i].Line = src.MapLine(loc.Line)
+		if i == 0 {
+			err.SetFile(err.Locations[i].Source)
+		}
+	}
+}
+
 type Location struct {
 	Line   int `json:"line,omitempty"`
 	Column int `json:"column,omitempty"`
+	// Source is the name of the source document, it is not part of the serialized error.
+	Source string `json:"-"`
+	// Start and End are the rune offsets of the token at the location, as in ast.Position, when the error was
+	// made from a position. They are not part of the serialized error.
+	Start int `json:"-"`
+	End   int `json:"-"`
 }
 
 type List []*Error
@@ -50,6 +75,10 @@ func (err *Error) Error() string {
 	if len(err.Locations) > 0 {
 		res.WriteByte(':')
 		res.WriteString(strconv.Itoa(err.Locations[0].Line))
+		if err.Locations[0].Column > 0 {
+			res.WriteByte(':')
+			res.WriteString(strconv.Itoa(err.Locations[0].Column))
+		}
 	}
 
 	res.WriteString(": ")
@@ -63,6 +92,42 @@ func (err *Error) Error() string {
 	return res.String()
 }
 
+// PrettyPrint writes the error followed by the source line of each location, with carets under the token at
+// the location. Sources are matched to locations by name, a single unnamed source matches any location.
+func (err *Error) PrettyPrint(w io.Writer, sources ...*ast.Source) error {
+	var buf bytes.Buffer
+	buf.WriteString(err.Error())
+	buf.WriteByte('\n')
+
+	for _, loc := range err.Locations {
+		src := findSource(sources, loc.Source)
+		if src == nil {
+			continue
+		}
+		pos := ast.Position{Start: loc.Start, End: loc.End, Line: loc.Line, Column: loc.Column, Src: src}
+		line, underline := pos.Snippet()
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		buf.WriteString(underline)
+		buf.WriteByte('\n')
+	}
+
+	_, writeErr := w.Write(buf.Bytes())
+	return writeErr
+}
+
+func findSource(sources []*ast.Source, name string) *ast.Source {
+	for _, src := range sources {
+		if src.Name == name {
+			return src
+		}
+	}
+	if len(sources) == 1 && sources[0].Name == "" {
+		return sources[0]
+	}
+	return nil
+}
+
 func (err Error) pathString() string {
 	return err.Path.String()
 }
@@ -80,6 +145,27 @@ func (errs List) Error() string {
 	return buf.String()
 }
 
+// Is reports whether any error in the list is target, or wraps it, so errors.Is looks through a List.
+func (errs List) Is(target error) bool {
+	for _, err := range errs {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in the list that matches target, so errors.As can pull a *Error, or an error
+// wrapped by one, out of a List.
+func (errs List) As(target interface{}) bool {
+	for _, err := range errs {
+		if err != nil && errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func WrapPath(path ast.Path, err error) *Error {
 	return &Error{
 		err:     err,
@@ -101,14 +187,26 @@ func ErrorPathf(path ast.Path, message string, args ...interface{}) *Error {
 	}
 }
 
+// ErrorPosf returns an error located at pos, or without a location if pos is nil, as it is for ast built in
+// code rather than parsed.
 func ErrorPosf(pos *ast.Position, message string, args ...interface{}) *Error {
-	return ErrorLocf(
-		pos.Src.Name,
+	if pos == nil {
+		return Errorf(message, args...)
+	}
+	var file string
+	if pos.Src != nil {
+		file = pos.Src.Name
+	}
+	err := ErrorLocf(
+		file,
 		pos.Line,
 		pos.Column,
 		message,
 		args...,
 	)
+	err.Locations[0].Start = pos.Start
+	err.Locations[0].End = pos.End
+	return err
 }
 
 func ErrorLocf(file string, line int, col int, message string, args ...interface{}) *Error {
@@ -120,7 +218,7 @@ func ErrorLocf(file string, line int, col int, message string, args ...interface
 		Message:    fmt.Sprintf(message, args...),
 		Extensions: extensions,
 		Locations: []Location{
-			{Line: line, Column: col},
+			{Line: line, Column: col, Source: file},
 		},
 	}
 }
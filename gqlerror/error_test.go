@@ -1,6 +1,9 @@
 package gqlerror
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
@@ -11,15 +14,16 @@ func TestErrorFormatting(t *testing.T) {
 	t.Run("without filename", func(t *testing.T) {
 		err := ErrorLocf("", 66, 2, "kabloom")
 
-		require.Equal(t, `input:66: kabloom`, err.Error())
+		require.Equal(t, `input:66:2: kabloom`, err.Error())
 		require.Equal(t, nil, err.Extensions["file"])
 	})
 
 	t.Run("with filename", func(t *testing.T) {
 		err := ErrorLocf("schema.graphql", 66, 2, "kabloom")
 
-		require.Equal(t, `schema.graphql:66: kabloom`, err.Error())
+		require.Equal(t, `schema.graphql:66:2: kabloom`, err.Error())
 		require.Equal(t, "schema.graphql", err.Extensions["file"])
+		require.Equal(t, "schema.graphql", err.Locations[0].Source)
 	})
 
 	t.Run("with path", func(t *testing.T) {
@@ -28,3 +32,116 @@ func TestErrorFormatting(t *testing.T) {
 		require.Equal(t, `input: a[1].b kabloom`, err.Error())
 	})
 }
+
+func TestErrorPrettyPrint(t *testing.T) {
+	src := &ast.Source{Name: "schema.graphql", Input: "type Query {\n\tname: Strng\n}\n"}
+	err := ErrorPosf(&ast.Position{Start: 20, End: 25, Line: 2, Column: 8, Src: src}, "Undefined type Strng.")
+
+	t.Run("with source", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, err.PrettyPrint(&buf, &ast.Source{Name: "other.graphql"}, src))
+		require.Equal(t, "schema.graphql:2:8: Undefined type Strng.\n\tname: Strng\n\t      ^^^^^\n", buf.String())
+	})
+
+	t.Run("without source", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, err.PrettyPrint(&buf))
+		require.Equal(t, "schema.graphql:2:8: Undefined type Strng.\n", buf.String())
+	})
+
+	t.Run("without an end", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, ErrorLocf("schema.graphql", 2, 8, "Undefined type Strng.").PrettyPrint(&buf, src))
+		require.Equal(t, "schema.graphql:2:8: Undefined type Strng.\n\tname: Strng\n\t      ^\n", buf.String())
+	})
+}
+
+func TestErrorJSON(t *testing.T) {
+	err := ErrorLocf("schema.graphql", 2, 8, "kabloom")
+	err.Path = ast.Path{ast.PathName("a"), ast.PathIndex(1)}
+
+	t.Run("default", func(t *testing.T) {
+		b, jsonErr := json.Marshal(err)
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"kabloom","locations":[{"line":2,"column":8}],"path":["a",1],"extensions":{"file":"schema.graphql"}}`, string(b))
+
+		b, jsonErr = json.Marshal(Errorf("plain"))
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"plain"}`, string(b))
+
+		var decoded Error
+		require.NoError(t, json.Unmarshal([]byte(`{"message":"kabloom","locations":[{"line":2,"column":8}],"path":["a",1]}`), &decoded))
+		require.Equal(t, "kabloom", decoded.Message)
+		require.Equal(t, []Location{{Line: 2, Column: 8}}, decoded.Locations)
+		require.Equal(t, err.Path, decoded.Path)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		f := NewFormatter(WithoutLocations(), WithoutPath(), WithExtensions(func(err *Error) map[string]interface{} {
+			return map[string]interface{}{"code": "GRAPHQL_VALIDATION_FAILED"}
+		}))
+		b, jsonErr := f.Marshal(err)
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"kabloom","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}`, string(b))
+
+		b, jsonErr = NewFormatter(WithExtensions(func(*Error) map[string]interface{} { return nil })).Marshal(err)
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"kabloom","locations":[{"line":2,"column":8}],"path":["a",1]}`, string(b))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		b, jsonErr := NewFormatter(WithoutLocations()).MarshalList(List{err, Errorf("plain")})
+		require.NoError(t, jsonErr)
+		require.Equal(t, `[{"message":"kabloom","path":["a",1],"extensions":{"file":"schema.graphql"}},{"message":"plain"}]`, string(b))
+
+		b, jsonErr = NewFormatter().MarshalList(nil)
+		require.NoError(t, jsonErr)
+		require.Equal(t, `[]`, string(b))
+	})
+}
+
+func TestErrorPosfWithoutPosition(t *testing.T) {
+	err := ErrorPosf(nil, "Undefined type %s.", "Foo")
+	require.Equal(t, "Undefined type Foo.", err.Message)
+	require.Empty(t, err.Locations)
+	require.Equal(t, "input: Undefined type Foo.", err.Error())
+}
+
+func TestErrorMapSource(t *testing.T) {
+	src := ast.ConcatSources("schema",
+		&ast.Source{Name: "a.graphql", Input: "type Query {\n\tuser: User\n}\n"},
+		&ast.Source{Name: "b.graphql", Input: "type User {\n\tid: ID!\n}\n"},
+	)
+
+	err := ErrorPosf(&ast.Position{Line: 5, Column: 7, Src: src}, "Undefined type ID.")
+	err.Locations = append(err.Locations, Location{Line: 2, Column: 8, Source: "schema"}, Location{Line: 5, Column: 1, Source: "other.graphql"})
+	err.MapSource(src)
+
+	require.Equal(t, []Location{
+		{Line: 2, Column: 7, Source: "b.graphql"},
+		{Line: 2, Column: 8, Source: "a.graphql"},
+		{Line: 5, Column: 1, Source: "other.graphql"},
+	}, err.Locations)
+	require.Equal(t, "b.graphql:2:7: Undefined type ID.", err.Error())
+}
+
+func TestListErrors(t *testing.T) {
+	cause := errors.New("connection refused")
+	list := List{
+		ErrorLocf("schema.graphql", 1, 2, "first"),
+		WrapPath(ast.Path{ast.PathName("user")}, cause),
+	}
+
+	var err error = list
+	require.Equal(t, "schema.graphql:1:2: first\ninput: user connection refused\n", err.Error())
+
+	var gqlErr *Error
+	require.True(t, errors.As(err, &gqlErr))
+	require.Equal(t, "first", gqlErr.Message)
+
+	require.True(t, errors.Is(err, cause))
+	require.True(t, errors.Is(err, list[0]))
+	require.False(t, errors.Is(err, errors.New("connection refused")))
+	require.False(t, errors.Is(List{}, cause))
+	require.False(t, errors.As(List{nil}, &gqlErr))
+}
@@ -0,0 +1,96 @@
+package gqlerror
+
+import (
+	"encoding/json"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+)
+
+// Formatter serializes errors to JSON for a transport, see NewFormatter. The zero value, and so MarshalJSON,
+// produces the shape described by the spec: {"message", "locations", "path", "extensions"}, leaving out any of
+// the last three that are empty.
+type Formatter struct {
+	withoutLocations bool
+	withoutPath      bool
+	extensions       func(err *Error) map[string]interface{}
+}
+
+// FormatOption configures a Formatter
+type FormatOption func(f *Formatter)
+
+// WithoutLocations leaves the locations out of serialized errors.
+func WithoutLocations() FormatOption {
+	return func(f *Formatter) {
+		f.withoutLocations = true
+	}
+}
+
+// WithoutPath leaves the path out of serialized errors.
+func WithoutPath() FormatOption {
+	return func(f *Formatter) {
+		f.withoutPath = true
+	}
+}
+
+// WithExtensions serializes the extensions returned by fn instead of the error's own, eg to add an Apollo style
+// "code" or to drop the "file" extension. Returning nil leaves extensions out.
+func WithExtensions(fn func(err *Error) map[string]interface{}) FormatOption {
+	return func(f *Formatter) {
+		f.extensions = fn
+	}
+}
+
+// NewFormatter returns a Formatter configured by opts.
+func NewFormatter(opts ...FormatOption) *Formatter {
+	f := &Formatter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// jsonError is the serialized form of an Error, with its fields in the order the spec lists them.
+type jsonError struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       ast.Path               `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (f *Formatter) format(err *Error) *jsonError {
+	res := &jsonError{
+		Message:    err.Message,
+		Locations:  err.Locations,
+		Path:       err.Path,
+		Extensions: err.Extensions,
+	}
+	if f.withoutLocations {
+		res.Locations = nil
+	}
+	if f.withoutPath {
+		res.Path = nil
+	}
+	if f.extensions != nil {
+		res.Extensions = f.extensions(err)
+	}
+	return res
+}
+
+// Marshal serializes a single error.
+func (f *Formatter) Marshal(err *Error) ([]byte, error) {
+	return json.Marshal(f.format(err))
+}
+
+// MarshalList serializes errs as a JSON array, an empty list is serialized as [].
+func (f *Formatter) MarshalList(errs List) ([]byte, error) {
+	res := make([]*jsonError, 0, len(errs))
+	for _, err := range errs {
+		res = append(res, f.format(err))
+	}
+	return json.Marshal(res)
+}
+
+// MarshalJSON serializes the error with a default Formatter.
+func (err Error) MarshalJSON() ([]byte, error) {
+	return (&Formatter{}).Marshal(&err)
+}
@@ -0,0 +1,293 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// BuildSchema reconstructs a schema from the JSON result of an introspection query. data may be the full
+// response, `{"data": {"__schema": ...}}`, just its data, `{"__schema": ...}`, or the `__schema` object itself.
+func BuildSchema(data []byte) (*ast.Schema, error) {
+	var response struct {
+		Data struct {
+			Schema *Schema `json:"__schema"`
+		} `json:"data"`
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+
+	schema := response.Data.Schema
+	if schema == nil {
+		schema = response.Schema
+	}
+	if schema == nil {
+		schema = &Schema{}
+		if err := json.Unmarshal(data, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return BuildSchemaFrom(schema)
+}
+
+// BuildSchemaFrom reconstructs a schema from a decoded introspection result. Built in scalars, introspection
+// types and built in directives come from the prelude rather than the introspection result.
+func BuildSchemaFrom(schema *Schema) (*ast.Schema, error) {
+	doc, gerr := parser.ParseSchema(validator.Prelude)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	b := builder{
+		src:     &ast.Source{Name: "introspection"},
+		builtIn: map[string]bool{},
+	}
+	for _, def := range doc.Definitions {
+		b.builtIn[def.Name] = true
+	}
+	for _, dir := range doc.Directives {
+		b.builtIn["@"+dir.Name] = true
+	}
+
+	sdl, err := b.document(schema)
+	if err != nil {
+		return nil, err
+	}
+	doc.Merge(sdl)
+
+	s, gerr := validator.ValidateSchemaDocument(doc)
+	if gerr != nil {
+		return nil, gerr
+	}
+	return s, nil
+}
+
+type builder struct {
+	src     *ast.Source
+	builtIn map[string]bool
+}
+
+func (b *builder) pos() *ast.Position {
+	return &ast.Position{Src: b.src}
+}
+
+func (b *builder) document(schema *Schema) (*ast.SchemaDocument, error) {
+	doc := &ast.SchemaDocument{}
+
+	def := &ast.SchemaDefinition{Position: b.pos()}
+	for _, root := range []struct {
+		op  ast.Operation
+		typ *TypeName
+	}{
+		{ast.Query, schema.QueryType},
+		{ast.Mutation, schema.MutationType},
+		{ast.Subscription, schema.SubscriptionType},
+	} {
+		if root.typ != nil {
+			def.OperationTypes = append(def.OperationTypes, &ast.OperationTypeDefinition{Operation: root.op, Type: root.typ.Name, Position: b.pos()})
+		}
+	}
+	if len(def.OperationTypes) != 0 {
+		doc.Schema = append(doc.Schema, def)
+	}
+
+	for _, typ := range schema.Types {
+		if b.builtIn[typ.Name] {
+			continue
+		}
+		def, err := b.definition(typ)
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+
+	for _, dir := range schema.Directives {
+		if b.builtIn["@"+dir.Name] {
+			continue
+		}
+		def := &ast.DirectiveDefinition{
+			Description:  str(dir.Description),
+			Name:         dir.Name,
+			IsRepeatable: dir.IsRepeatable,
+			Position:     b.pos(),
+		}
+		for _, loc := range dir.Locations {
+			def.Locations = append(def.Locations, ast.DirectiveLocation(loc))
+		}
+		args, err := b.arguments(dir.Args)
+		if err != nil {
+			return nil, err
+		}
+		def.Arguments = args
+		doc.Directives = append(doc.Directives, def)
+	}
+
+	return doc, nil
+}
+
+func (b *builder) definition(typ FullType) (*ast.Definition, error) {
+	def := &ast.Definition{
+		Kind:        ast.DefinitionKind(typ.Kind),
+		Name:        typ.Name,
+		Description: str(typ.Description),
+		Position:    b.pos(),
+	}
+
+	switch def.Kind {
+	case ast.Scalar, ast.Enum, ast.Union:
+	case ast.Object, ast.Interface:
+		for _, iface := range typ.Interfaces {
+			def.Interfaces = append(def.Interfaces, str(iface.Name))
+		}
+		for _, field := range typ.Fields {
+			args, err := b.arguments(field.Args)
+			if err != nil {
+				return nil, err
+			}
+			t, err := b.typeRef(&field.Type)
+			if err != nil {
+				return nil, err
+			}
+			def.Fields = append(def.Fields, &ast.FieldDefinition{
+				Description: str(field.Description),
+				Name:        field.Name,
+				Arguments:   args,
+				Type:        t,
+				Directives:  b.deprecated(field.IsDeprecated, field.DeprecationReason),
+				Position:    b.pos(),
+			})
+		}
+	case ast.InputObject:
+		for _, field := range typ.InputFields {
+			t, err := b.typeRef(&field.Type)
+			if err != nil {
+				return nil, err
+			}
+			defaultValue, err := b.value(field.DefaultValue)
+			if err != nil {
+				return nil, err
+			}
+			def.Fields = append(def.Fields, &ast.FieldDefinition{
+				Description:  str(field.Description),
+				Name:         field.Name,
+				DefaultValue: defaultValue,
+				Type:         t,
+				Directives:   b.deprecated(field.IsDeprecated, field.DeprecationReason),
+				Position:     b.pos(),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("type %s has unknown kind %s", typ.Name, typ.Kind)
+	}
+
+	if def.Kind == ast.Union {
+		for _, possible := range typ.PossibleTypes {
+			def.Types = append(def.Types, str(possible.Name))
+		}
+	}
+	if def.Kind == ast.Enum {
+		for _, value := range typ.EnumValues {
+			def.EnumValues = append(def.EnumValues, &ast.EnumValueDefinition{
+				Description: str(value.Description),
+				Name:        value.Name,
+				Directives:  b.deprecated(value.IsDeprecated, value.DeprecationReason),
+				Position:    b.pos(),
+			})
+		}
+	}
+
+	return def, nil
+}
+
+func (b *builder) arguments(values []InputValue) (ast.ArgumentDefinitionList, error) {
+	var args ast.ArgumentDefinitionList
+	for _, value := range values {
+		t, err := b.typeRef(&value.Type)
+		if err != nil {
+			return nil, err
+		}
+		defaultValue, err := b.value(value.DefaultValue)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &ast.ArgumentDefinition{
+			Description:  str(value.Description),
+			Name:         value.Name,
+			DefaultValue: defaultValue,
+			Type:         t,
+			Directives:   b.deprecated(value.IsDeprecated, value.DeprecationReason),
+			Position:     b.pos(),
+		})
+	}
+	return args, nil
+}
+
+func (b *builder) typeRef(ref *TypeRef) (*ast.Type, error) {
+	switch ref.Kind {
+	case "NON_NULL":
+		if ref.OfType == nil {
+			return nil, fmt.Errorf("NON_NULL type reference is missing ofType")
+		}
+		t, err := b.typeRef(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		t.NonNull = true
+		return t, nil
+	case "LIST":
+		if ref.OfType == nil {
+			return nil, fmt.Errorf("LIST type reference is missing ofType")
+		}
+		elem, err := b.typeRef(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return ast.ListType(elem, b.pos()), nil
+	default:
+		if ref.Name == nil {
+			return nil, fmt.Errorf("%s type reference is missing a name", ref.Kind)
+		}
+		return ast.NamedType(*ref.Name, b.pos()), nil
+	}
+}
+
+// value parses a default value, which introspection returns as a printed literal.
+func (b *builder) value(literal *string) (*ast.Value, error) {
+	if literal == nil {
+		return nil, nil
+	}
+	value, err := parser.ParseValue(&ast.Source{Name: "introspection", Input: *literal})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (b *builder) deprecated(isDeprecated bool, reason *string) ast.DirectiveList {
+	if !isDeprecated {
+		return nil
+	}
+	dir := &ast.Directive{Name: "deprecated", Position: b.pos()}
+	if reason != nil {
+		dir.Arguments = ast.ArgumentList{{
+			Name:     "reason",
+			Value:    &ast.Value{Kind: ast.StringValue, Raw: *reason, Position: b.pos()},
+			Position: b.pos(),
+		}}
+	}
+	return ast.DirectiveList{dir}
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
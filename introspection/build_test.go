@@ -0,0 +1,75 @@
+package introspection
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/formatter"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestBuildSchema(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/introspection.json")
+	require.NoError(t, err)
+
+	schema, err := BuildSchema(data)
+	require.NoError(t, err)
+
+	require.Equal(t, "Query", schema.Query.Name)
+	require.Nil(t, schema.Mutation)
+	require.Equal(t, "The query root", schema.Query.Description)
+
+	users := schema.Query.Fields.ForName("users")
+	require.Equal(t, "[User!]!", users.Type.String())
+	require.Equal(t, `{role:ADMIN,names:["a","b"]}`, users.Arguments.ForName("filter").DefaultValue.String())
+	require.Equal(t, "10", users.Arguments.ForName("first").DefaultValue.String())
+	require.Equal(t, "The node id", schema.Query.Fields.ForName("node").Arguments.ForName("id").Description)
+
+	deprecated, reason := schema.Query.Fields.ForName("search").IsDeprecated()
+	require.True(t, deprecated)
+	require.Equal(t, "Use users", reason)
+	deprecated, _ = schema.Types["Role"].EnumValues.ForName("GUEST").IsDeprecated()
+	require.True(t, deprecated)
+
+	require.Equal(t, []string{"Node"}, schema.Types["User"].Interfaces)
+	require.Equal(t, []string{"User"}, schema.Types["SearchResult"].Types)
+	require.Equal(t, "GUEST", schema.Types["UserFilter"].Fields.ForName("role").DefaultValue.Raw)
+	require.Equal(t, ast.Scalar, schema.Types["Date"].Kind)
+	require.Equal(t, "An ISO date", schema.Types["Date"].Description)
+	require.True(t, schema.Types["String"].BuiltIn)
+	require.Equal(t, []ast.DirectiveLocation{ast.LocationField, ast.LocationFragmentSpread}, schema.Directives["cached"].Locations)
+	require.True(t, schema.Directives["skip"].Position.Src.BuiltIn)
+
+	query, gerr := parser.ParseQuery(&ast.Source{Input: `{ users(first: 1) @cached { id ... on User { role } } node(id: "1") { id } }`})
+	require.Nil(t, gerr)
+	require.Empty(t, validator.Validate(schema, query, nil))
+
+	t.Run("formatted schema loads", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf).FormatSchema(schema)
+
+		reloaded, gerr := validator.LoadSchema(validator.Prelude, &ast.Source{Name: "sdl", Input: buf.String()})
+		require.Nil(t, gerr, buf.String())
+		require.Equal(t, "[User!]!", reloaded.Query.Fields.ForName("users").Type.String())
+	})
+
+	t.Run("accepts the schema object", func(t *testing.T) {
+		schema, err := BuildSchema([]byte(`{"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "ok", "args": [], "type": {"kind": "SCALAR", "name": "Boolean"}}
+		]}], "directives": []}`))
+		require.NoError(t, err)
+		require.NotNil(t, schema.Query.Fields.ForName("ok"))
+	})
+
+	t.Run("invalid default value", func(t *testing.T) {
+		_, err := BuildSchema([]byte(`{"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "ok", "args": [{"name": "a", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "1 2"}], "type": {"kind": "SCALAR", "name": "Boolean"}}
+		]}], "directives": []}}`))
+		require.Error(t, err)
+	})
+}
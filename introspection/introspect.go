@@ -0,0 +1,223 @@
+package introspection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+)
+
+// IntrospectionResult is the data returned by the standard introspection query.
+type IntrospectionResult struct {
+	Schema Schema `json:"__schema"`
+}
+
+// Introspect builds the result of the standard introspection query for schema, as served to clients like
+// GraphiQL. Deprecated fields and enum values are included, and default values are printed as literals.
+// Types and directives are sorted by name, and the implicit __schema and __type meta fields are left out.
+func Introspect(schema *ast.Schema) (*IntrospectionResult, error) {
+	if schema.Query == nil {
+		return nil, fmt.Errorf("schema has no query type")
+	}
+
+	result := &IntrospectionResult{Schema: Schema{
+		QueryType:        typeName(schema.Query),
+		MutationType:     typeName(schema.Mutation),
+		SubscriptionType: typeName(schema.Subscription),
+		Types:            []FullType{},
+		Directives:       []Directive{},
+	}}
+
+	var names []string
+	for name := range schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		typ, err := fullType(schema, schema.Types[name])
+		if err != nil {
+			return nil, err
+		}
+		result.Schema.Types = append(result.Schema.Types, typ)
+	}
+
+	names = names[:0]
+	for name := range schema.Directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := schema.Directives[name]
+		dir := Directive{
+			Name:         def.Name,
+			Description:  description(def.Description),
+			Locations:    []string{},
+			Args:         inputValues(schema, def.Arguments),
+			IsRepeatable: def.IsRepeatable,
+		}
+		for _, loc := range def.Locations {
+			dir.Locations = append(dir.Locations, string(loc))
+		}
+		result.Schema.Directives = append(result.Schema.Directives, dir)
+	}
+
+	return result, nil
+}
+
+func fullType(schema *ast.Schema, def *ast.Definition) (FullType, error) {
+	typ := FullType{
+		Kind:        string(def.Kind),
+		Name:        def.Name,
+		Description: description(def.Description),
+	}
+
+	switch def.Kind {
+	case ast.Scalar:
+		if dir := def.Directives.ForName("specifiedBy"); dir != nil {
+			if url := dir.Arguments.ForName("url"); url != nil && url.Value != nil {
+				typ.SpecifiedByURL = &url.Value.Raw
+			}
+		}
+
+	case ast.Object, ast.Interface:
+		typ.Fields = []Field{}
+		for _, f := range def.Fields {
+			// meta fields like __schema and __type are implicit and not part of the introspection result
+			if strings.HasPrefix(f.Name, "__") {
+				continue
+			}
+			deprecated, reason := f.IsDeprecated()
+			field := Field{
+				Name:         f.Name,
+				Description:  description(f.Description),
+				Args:         inputValues(schema, f.Arguments),
+				Type:         typeRef(schema, f.Type),
+				IsDeprecated: deprecated,
+			}
+			if deprecated {
+				field.DeprecationReason = &reason
+			}
+			typ.Fields = append(typ.Fields, field)
+		}
+		typ.Interfaces = []TypeRef{}
+		for _, name := range def.Interfaces {
+			typ.Interfaces = append(typ.Interfaces, namedTypeRef(schema, name))
+		}
+		if def.Kind == ast.Interface {
+			typ.PossibleTypes = possibleTypes(schema, def)
+		}
+
+	case ast.Union:
+		typ.PossibleTypes = possibleTypes(schema, def)
+
+	case ast.Enum:
+		typ.EnumValues = []EnumValue{}
+		for _, v := range def.EnumValues {
+			deprecated, reason := v.IsDeprecated()
+			value := EnumValue{
+				Name:         v.Name,
+				Description:  description(v.Description),
+				IsDeprecated: deprecated,
+			}
+			if deprecated {
+				value.DeprecationReason = &reason
+			}
+			typ.EnumValues = append(typ.EnumValues, value)
+		}
+
+	case ast.InputObject:
+		typ.InputFields = []InputValue{}
+		for _, f := range def.Fields {
+			value := inputValue(schema, f.Name, f.Description, f.Type, f.DefaultValue)
+			value.IsDeprecated, value.DeprecationReason = deprecationReason(f.IsDeprecated())
+			typ.InputFields = append(typ.InputFields, value)
+		}
+
+	default:
+		return typ, fmt.Errorf("type %s has unknown kind %s", def.Name, def.Kind)
+	}
+
+	return typ, nil
+}
+
+func possibleTypes(schema *ast.Schema, def *ast.Definition) []TypeRef {
+	var names []string
+	for _, possible := range schema.GetPossibleTypes(def) {
+		names = append(names, possible.Name)
+	}
+	sort.Strings(names)
+
+	refs := []TypeRef{}
+	for _, name := range names {
+		refs = append(refs, namedTypeRef(schema, name))
+	}
+	return refs
+}
+
+func inputValues(schema *ast.Schema, args ast.ArgumentDefinitionList) []InputValue {
+	values := []InputValue{}
+	for _, arg := range args {
+		value := inputValue(schema, arg.Name, arg.Description, arg.Type, arg.DefaultValue)
+		value.IsDeprecated, value.DeprecationReason = deprecationReason(arg.IsDeprecated())
+		values = append(values, value)
+	}
+	return values
+}
+
+// deprecationReason converts the result of an IsDeprecated accessor, the reason is only set when deprecated.
+func deprecationReason(deprecated bool, reason string) (bool, *string) {
+	if !deprecated {
+		return false, nil
+	}
+	return true, &reason
+}
+
+func inputValue(schema *ast.Schema, name, desc string, typ *ast.Type, defaultValue *ast.Value) InputValue {
+	value := InputValue{
+		Name:        name,
+		Description: description(desc),
+		Type:        typeRef(schema, typ),
+	}
+	if defaultValue != nil {
+		literal := defaultValue.String()
+		value.DefaultValue = &literal
+	}
+	return value
+}
+
+func typeRef(schema *ast.Schema, typ *ast.Type) TypeRef {
+	var ref TypeRef
+	if typ.Elem != nil {
+		elem := typeRef(schema, typ.Elem)
+		ref = TypeRef{Kind: "LIST", OfType: &elem}
+	} else {
+		ref = namedTypeRef(schema, typ.NamedType)
+	}
+	if typ.NonNull {
+		return TypeRef{Kind: "NON_NULL", OfType: &ref}
+	}
+	return ref
+}
+
+func namedTypeRef(schema *ast.Schema, name string) TypeRef {
+	ref := TypeRef{Name: &name}
+	if def := schema.Types[name]; def != nil {
+		ref.Kind = string(def.Kind)
+	}
+	return ref
+}
+
+func typeName(def *ast.Definition) *TypeName {
+	if def == nil {
+		return nil
+	}
+	return &TypeName{Name: def.Name}
+}
+
+func description(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
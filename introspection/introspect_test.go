@@ -0,0 +1,117 @@
+package introspection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+func TestIntrospect(t *testing.T) {
+	schema, gerr := validator.LoadSchema(validator.Prelude, &ast.Source{Name: "schema", Input: `
+		directive @specifiedBy(url: String!) on SCALAR
+		directive @cached(maxAge: Int = 60) on FIELD
+
+		"The query root"
+		type Query {
+			users(filter: UserFilter = {role: ADMIN, names: ["a"]}, first: Int = 10 @deprecated(reason: "Use page")): [User!]!
+			node(id: ID!): Node
+			search: [SearchResult] @deprecated(reason: "Use users")
+		}
+		type Mutation { ping: Boolean }
+		interface Node { id: ID! }
+		type User implements Node {
+			id: ID!
+			role: Role
+			joined: Date
+		}
+		union SearchResult = User
+		enum Role {
+			ADMIN
+			"Not signed in"
+			GUEST @deprecated
+		}
+		input UserFilter {
+			role: Role = GUEST
+			names: [String!] @deprecated
+		}
+		scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+	`})
+	require.Nil(t, gerr)
+
+	result, err := Introspect(schema)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var shape struct {
+		Schema struct {
+			QueryType        map[string]interface{}   `json:"queryType"`
+			MutationType     map[string]interface{}   `json:"mutationType"`
+			SubscriptionType interface{}              `json:"subscriptionType"`
+			Types            []map[string]interface{} `json:"types"`
+		} `json:"__schema"`
+	}
+	require.NoError(t, json.Unmarshal(data, &shape))
+	require.Equal(t, "Query", shape.Schema.QueryType["name"])
+	require.Equal(t, "Mutation", shape.Schema.MutationType["name"])
+	require.Nil(t, shape.Schema.SubscriptionType)
+
+	types := map[string]map[string]interface{}{}
+	for _, typ := range shape.Schema.Types {
+		types[typ["name"].(string)] = typ
+	}
+	require.Equal(t, "https://tools.ietf.org/html/rfc3339", types["Date"]["specifiedByURL"])
+	require.Nil(t, types["Date"]["fields"])
+	require.Contains(t, types, "__Schema")
+
+	users := types["Query"]["fields"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "users", users["name"])
+	require.Equal(t, false, users["isDeprecated"])
+	require.Nil(t, users["deprecationReason"])
+	require.Equal(t, map[string]interface{}{
+		"kind": "NON_NULL",
+		"name": nil,
+		"ofType": map[string]interface{}{
+			"kind": "LIST",
+			"name": nil,
+			"ofType": map[string]interface{}{
+				"kind":   "NON_NULL",
+				"name":   nil,
+				"ofType": map[string]interface{}{"kind": "OBJECT", "name": "User", "ofType": nil},
+			},
+		},
+	}, users["type"])
+	args := users["args"].([]interface{})
+	require.Equal(t, `{role:ADMIN,names:["a"]}`, args[0].(map[string]interface{})["defaultValue"])
+	require.Equal(t, false, args[0].(map[string]interface{})["isDeprecated"])
+	require.Nil(t, args[0].(map[string]interface{})["deprecationReason"])
+	require.Equal(t, "10", args[1].(map[string]interface{})["defaultValue"])
+	require.Equal(t, true, args[1].(map[string]interface{})["isDeprecated"])
+	require.Equal(t, "Use page", args[1].(map[string]interface{})["deprecationReason"])
+
+	names := types["UserFilter"]["inputFields"].([]interface{})[1].(map[string]interface{})
+	require.Equal(t, "names", names["name"])
+	require.Equal(t, true, names["isDeprecated"])
+	require.Equal(t, "No longer supported", names["deprecationReason"])
+
+	search := types["Query"]["fields"].([]interface{})[2].(map[string]interface{})
+	require.Equal(t, "search", search["name"])
+	require.Equal(t, true, search["isDeprecated"])
+	require.Equal(t, "Use users", search["deprecationReason"])
+
+	guest := types["Role"]["enumValues"].([]interface{})[1].(map[string]interface{})
+	require.Equal(t, "Not signed in", guest["description"])
+	require.Equal(t, true, guest["isDeprecated"])
+	require.Equal(t, "No longer supported", guest["deprecationReason"])
+
+	t.Run("round trips through BuildSchema", func(t *testing.T) {
+		rebuilt, err := BuildSchema(data)
+		require.NoError(t, err)
+		require.Empty(t, ast.Diff(schema, rebuilt))
+	})
+}
@@ -0,0 +1,67 @@
+// Package introspection converts between *ast.Schema and the result of the standard introspection query.
+package introspection
+
+// Schema is the value of `__schema` in an introspection result.
+type Schema struct {
+	QueryType        *TypeName   `json:"queryType"`
+	MutationType     *TypeName   `json:"mutationType"`
+	SubscriptionType *TypeName   `json:"subscriptionType"`
+	Types            []FullType  `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+type FullType struct {
+	Kind           string       `json:"kind"`
+	Name           string       `json:"name"`
+	Description    *string      `json:"description"`
+	SpecifiedByURL *string      `json:"specifiedByURL"`
+	Fields         []Field      `json:"fields"`
+	InputFields    []InputValue `json:"inputFields"`
+	Interfaces     []TypeRef    `json:"interfaces"`
+	EnumValues     []EnumValue  `json:"enumValues"`
+	PossibleTypes  []TypeRef    `json:"possibleTypes"`
+}
+
+type Field struct {
+	Name              string       `json:"name"`
+	Description       *string      `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+type InputValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	Type              TypeRef `json:"type"`
+	DefaultValue      *string `json:"defaultValue"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type Directive struct {
+	Name         string       `json:"name"`
+	Description  *string      `json:"description"`
+	Locations    []string     `json:"locations"`
+	Args         []InputValue `json:"args"`
+	IsRepeatable bool         `json:"isRepeatable"`
+}
+
+// TypeRef is a possibly wrapped type reference. Kind is NON_NULL or LIST for wrapping types, which set OfType.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
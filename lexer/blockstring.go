@@ -5,15 +5,18 @@ import (
 	"strings"
 )
 
-// blockStringValue produces the value of a block string from its parsed raw value, similar to
-// Coffeescript's block string, Python's docstring trim or Ruby's strip_heredoc.
+// BlockStringValue produces the value of a block string from its raw value, the text between the triple quotes
+// with escaped triple quotes already unescaped. It is similar to Coffeescript's block string, Python's docstring
+// trim or Ruby's strip_heredoc: the indentation common to every line but the first is removed, and so are
+// leading and trailing blank lines. Lines may end with \n, \r\n or \r, the value always uses \n.
 //
 // This implements the GraphQL spec's BlockStringValue() static algorithm.
-func blockStringValue(raw string) string {
-	lines := strings.Split(raw, "\n")
+func BlockStringValue(raw string) string {
+	lines := strings.Split(lineTerminators.Replace(raw), "\n")
 
+	// the first line follows the opening quotes, so its indentation isn't part of the block's
 	commonIndent := math.MaxInt32
-	for _, line := range lines {
+	for _, line := range lines[1:] {
 		indent := leadingWhitespace(line)
 		if indent < len(line) && indent < commonIndent {
 			commonIndent = indent
@@ -47,6 +50,8 @@ func blockStringValue(raw string) string {
 	return strings.Join(lines[start:end], "\n")
 }
 
+var lineTerminators = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
 func leadingWhitespace(str string) int {
 	for i, r := range str {
 		if r != ' ' && r != '\t' {
@@ -8,7 +8,7 @@ import (
 
 func TestBlockStringValue(t *testing.T) {
 	t.Run("removes uniform indentation from a string", func(t *testing.T) {
-		result := blockStringValue(`
+		result := BlockStringValue(`
     Hello,
       World!
 
@@ -19,7 +19,7 @@ func TestBlockStringValue(t *testing.T) {
 	})
 
 	t.Run("removes empty leading and trailing lines", func(t *testing.T) {
-		result := blockStringValue(`
+		result := BlockStringValue(`
 
 
     Hello,
@@ -34,7 +34,7 @@ func TestBlockStringValue(t *testing.T) {
 	})
 
 	t.Run("removes blank and trailing newlines", func(t *testing.T) {
-		result := blockStringValue(`
+		result := BlockStringValue(`
 
        
     Hello,
@@ -50,7 +50,7 @@ func TestBlockStringValue(t *testing.T) {
 	})
 
 	t.Run("does not alter trailing spaces", func(t *testing.T) {
-		result := blockStringValue(`
+		result := BlockStringValue(`
 
                 
     Hello,      
@@ -65,3 +65,33 @@ func TestBlockStringValue(t *testing.T) {
 		require.Equal(t, "Hello,      \n  World!    \n            \nYours,      \n  GraphQL.  ", result)
 	})
 }
+
+func TestBlockStringValueSpecExamples(t *testing.T) {
+	t.Run("ignores the indentation of the first line", func(t *testing.T) {
+		require.Equal(t, "  Hello,\nWorld!", BlockStringValue("  Hello,\n    World!"))
+		require.Equal(t, "Hello,\nWorld!", BlockStringValue("Hello,\n    World!\n  "))
+	})
+
+	t.Run("mixed indentation counts characters", func(t *testing.T) {
+		require.Equal(t, "a\n  b\n\tc", BlockStringValue("\n\t  a\n\t    b\n\t  \tc"))
+		require.Equal(t, "a\n b", BlockStringValue("\n\ta\n\t b"))
+	})
+
+	t.Run("lines shorter than the common indent are emptied", func(t *testing.T) {
+		require.Equal(t, "a\n\nb", BlockStringValue("\n    a\n  \n    b"))
+	})
+
+	t.Run("line terminators", func(t *testing.T) {
+		require.Equal(t, "Hello,\n  World!", BlockStringValue("\r\n    Hello,\r\n      World!\r\n"))
+		require.Equal(t, "Hello,\n  World!", BlockStringValue("\r    Hello,\r      World!\r"))
+	})
+
+	t.Run("only blank lines", func(t *testing.T) {
+		require.Equal(t, "", BlockStringValue(""))
+		require.Equal(t, "", BlockStringValue("  \n\t\n"))
+	})
+
+	t.Run("single line", func(t *testing.T) {
+		require.Equal(t, "  Hello  ", BlockStringValue("  Hello  "))
+	})
+}
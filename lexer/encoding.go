@@ -0,0 +1,58 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+)
+
+// Encoding converts input in a character encoding other than UTF-8 into UTF-8, see WithEncoding.
+type Encoding func(input string) string
+
+// Latin1 decodes ISO-8859-1, where every byte is the code point of the same value.
+func Latin1(input string) string {
+	var sb strings.Builder
+	sb.Grow(len(input))
+	for i := 0; i < len(input); i++ {
+		sb.WriteRune(rune(input[i]))
+	}
+	return sb.String()
+}
+
+// windows1252 holds the code points of 0x80 to 0x9F, which Windows-1252 uses for printable characters instead
+// of the C1 controls of latin-1. The five bytes it leaves undefined keep their latin-1 value.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// Windows1252 decodes Windows-1252, the superset of latin-1 most often produced by Windows tools.
+func Windows1252(input string) string {
+	var sb strings.Builder
+	sb.Grow(len(input))
+	for i := 0; i < len(input); i++ {
+		if b := input[i]; b >= 0x80 && b <= 0x9F {
+			sb.WriteRune(windows1252[b-0x80])
+		} else {
+			sb.WriteRune(rune(b))
+		}
+	}
+	return sb.String()
+}
+
+// WithEncoding converts the input from enc to UTF-8 before lexing it. Tokens and errors refer to a copy of the
+// source holding the converted input, so their positions are offsets into the converted input. Without it the
+// input must be UTF-8. Built in sources, such as the prelude, are always UTF-8 and never converted.
+func WithEncoding(enc Encoding) LexerOption {
+	return func(l *Lexer) {
+		l.encoding = enc
+	}
+}
+
+func decodeSource(src *ast.Source, enc Encoding) *ast.Source {
+	return &ast.Source{
+		Name:    src.Name,
+		Input:   enc(src.Input),
+		BuiltIn: src.BuiltIn,
+	}
+}
@@ -23,13 +23,91 @@ type Lexer struct {
 	line int
 	// An offset into the string in rune
 	lineStartRunes int
+	// accept vertical tabs and form feeds in strings
+	lenient bool
+	// keep the comments preceding each token
+	keepComments bool
+	comments     []Token
+	// the line the previous token ended on
+	prevLine int
+	// whether nothing but whitespace has been read since the last newline, and whether a whole line was empty
+	lineEmpty bool
+	blankLine bool
+	// converts the input to UTF-8, nil if it already is
+	encoding Encoding
+	// set by WithoutLocations
+	noLocations bool
 }
 
-func New(src *ast.Source) Lexer {
-	return Lexer{
+type LexerOption func(l *Lexer)
+
+// WithLenientStrings accepts the vertical tab (U+000B) and form feed (U+000C) characters inside
+// string and block string literals, normalizing each of them to a single space. The spec's
+// SourceCharacter disallows them, but some legacy tools emit them in descriptions.
+// All other control characters are still rejected.
+func WithLenientStrings() LexerOption {
+	return func(l *Lexer) {
+		l.lenient = true
+	}
+}
+
+// WithComments keeps the comments read before each token, see Comments.
+func WithComments() LexerOption {
+	return func(l *Lexer) {
+		l.keepComments = true
+	}
+}
+
+// WithoutLocations tells the parser to leave the Position of every ast node nil, saving an allocation per node for
+// documents that are only kept around to be executed or cached. Tokens still have positions, so parse errors are
+// located as usual, but errors found later, eg by validation, have no locations.
+func WithoutLocations() LexerOption {
+	return func(l *Lexer) {
+		l.noLocations = true
+	}
+}
+
+// Locations reports whether the parser should record the positions of ast nodes, which is the default.
+func (s *Lexer) Locations() bool {
+	return !s.noLocations
+}
+
+func New(src *ast.Source, opts ...LexerOption) Lexer {
+	l := Lexer{
 		Source: src,
 		line:   1,
 	}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	if l.encoding != nil && !src.BuiltIn {
+		l.Source = decodeSource(src, l.encoding)
+	}
+	return l
+}
+
+// Reset makes the lexer read input from the beginning, keeping any options it was created with, so a single
+// lexer can be reused across many small inputs. The input gets a new Source with the name of the previous one, and
+// the Source given to New is left as it was. Reset only clears the lexer, the parser's Parse functions create a new
+// lexer and parser for every document so they have no peeked tokens that would need resetting.
+func (s *Lexer) Reset(input string) {
+	src := &ast.Source{Input: input}
+	if s.Source != nil {
+		src.Name = s.Source.Name
+		src.BuiltIn = s.Source.BuiltIn
+	}
+	if s.encoding != nil && !src.BuiltIn {
+		src.Input = s.encoding(input)
+	}
+
+	*s = Lexer{
+		Source:       src,
+		line:         1,
+		lenient:      s.lenient,
+		keepComments: s.keepComments,
+		encoding:     s.encoding,
+		noLocations:  s.noLocations,
+	}
 }
 
 // take one rune from input and advance end
@@ -75,8 +153,24 @@ func (s *Lexer) makeError(format string, args ...interface{}) (Token, *gqlerror.
 // token, then lexes punctuators immediately or calls the appropriate helper
 // function for more complicated tokens.
 func (s *Lexer) ReadToken() (token Token, err *gqlerror.Error) {
+	s.comments = nil
+	s.lineEmpty = false
+	s.blankLine = false
+	token, err = s.readToken()
+	token.BlankLineBefore = s.blankLine
+	s.prevLine = s.line
+	return token, err
+}
 
-	s.ws()
+// Comments returns the comments between the previous token and the last one read by ReadToken, for lexers
+// created WithComments. Comments on the same line as the previous token are trailing comments of that token and
+// aren't included.
+func (s *Lexer) Comments() []Token {
+	return s.comments
+}
+
+func (s *Lexer) readToken() (token Token, err *gqlerror.Error) {
+	s.skipIgnored()
 	s.start = s.end
 	s.startRunes = s.endRunes
 
@@ -120,10 +214,6 @@ func (s *Lexer) ReadToken() (token Token, err *gqlerror.Error) {
 		return s.makeValueToken(BraceR, "")
 	case '|':
 		return s.makeValueToken(Pipe, "")
-	case '#':
-		s.readComment()
-		return s.ReadToken()
-
 	case '_', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 		return s.readName()
 
@@ -142,7 +232,11 @@ func (s *Lexer) ReadToken() (token Token, err *gqlerror.Error) {
 	s.endRunes--
 
 	if r < 0x0020 && r != 0x0009 && r != 0x000a && r != 0x000d {
-		return s.makeError(`Cannot contain the invalid character "\u%04d"`, r)
+		return s.makeError(`Cannot contain the invalid character "\u%04x"`, r)
+	}
+
+	if r == '+' && s.start+1 < len(s.Input) && s.Input[s.start+1] >= '0' && s.Input[s.start+1] <= '9' {
+		return s.makeError(`Invalid number, unexpected "+" sign; GraphQL does not allow a leading plus.`)
 	}
 
 	if r == '\'' {
@@ -152,6 +246,28 @@ func (s *Lexer) ReadToken() (token Token, err *gqlerror.Error) {
 	return s.makeError(`Cannot parse the unexpected character "%s".`, string(r))
 }
 
+// skipIgnored skips whitespace and comments up to the next token, keeping the comments for lexers created
+// WithComments. Comments are skipped in a loop rather than by reading the next token recursively, so long runs of
+// comment lines don't grow the stack.
+func (s *Lexer) skipIgnored() {
+	for {
+		s.ws()
+		if s.end >= len(s.Input) || s.Input[s.end] != '#' {
+			return
+		}
+
+		s.start = s.end
+		s.startRunes = s.endRunes
+		s.end++
+		s.endRunes++
+		comment, _ := s.readComment()
+		s.lineEmpty = false
+		if s.keepComments && comment.Pos.Line > s.prevLine {
+			s.comments = append(s.comments, comment)
+		}
+	}
+}
+
 // ws reads from body starting at startPosition until it finds a non-whitespace
 // or commented character, and updates the token end to include all whitespace
 func (s *Lexer) ws() {
@@ -161,20 +277,22 @@ func (s *Lexer) ws() {
 			s.end++
 			s.endRunes++
 		case '\n':
+			s.newline()
 			s.end++
 			s.endRunes++
 			s.line++
 			s.lineStartRunes = s.endRunes
 		case '\r':
+			s.newline()
 			s.end++
 			s.endRunes++
 			s.line++
-			s.lineStartRunes = s.endRunes
 			// skip the following newline if its there
 			if s.end < len(s.Input) && s.Input[s.end] == '\n' {
 				s.end++
 				s.endRunes++
 			}
+			s.lineStartRunes = s.endRunes
 			// byte order mark, given ws is hot path we aren't relying on the unicode package here.
 		case 0xef:
 			if s.end+2 < len(s.Input) && s.Input[s.end+1] == 0xBB && s.Input[s.end+2] == 0xBF {
@@ -189,6 +307,14 @@ func (s *Lexer) ws() {
 	}
 }
 
+// newline records whether the line that just ended was empty.
+func (s *Lexer) newline() {
+	if s.lineEmpty {
+		s.blankLine = true
+	}
+	s.lineEmpty = true
+}
+
 // readComment from the input
 //
 // #[\u0009\u0020-\uFFFF]*
@@ -208,11 +334,18 @@ func (s *Lexer) readComment() (Token, *gqlerror.Error) {
 	return s.makeToken(Comment)
 }
 
+// maxNumberLength bounds the length of number literals, see readNumber.
+const maxNumberLength = 1000
+
 // readNumber from the input, either a float
 // or an int depending on whether a decimal point appears.
 //
 // Int:   -?(0|[1-9][0-9]*)
 // Float: -?(0|[1-9][0-9]*)(\.[0-9]+)?((E|e)(+|-)?[0-9]+)?
+//
+// A number can be at most maxNumberLength characters long, sign and exponent included, which is far more than any
+// value that fits in an int64 or float64 needs. Within that any exponent lexes, eg 1e999999999, the token value
+// is just a slice of the input, and range is only checked when the value is converted, see ast.Value.Value.
 func (s *Lexer) readNumber() (Token, *gqlerror.Error) {
 	float := false
 
@@ -252,6 +385,11 @@ func (s *Lexer) readNumber() (Token, *gqlerror.Error) {
 		}
 	}
 
+	if s.end-s.start > maxNumberLength {
+		s.end, s.endRunes = s.start, s.startRunes
+		return s.makeError("Invalid number, longer than %d characters.", maxNumberLength)
+	}
+
 	if float {
 		return s.makeToken(Float)
 	} else {
@@ -314,8 +452,17 @@ func (s *Lexer) readString() (Token, *gqlerror.Error) {
 		if r == '\n' || r == '\r' {
 			break
 		}
+		if s.lenient && (r == '\v' || r == '\f') {
+			if buf == nil {
+				buf = bytes.NewBufferString(s.Input[s.start:s.end])
+			}
+			buf.WriteByte(' ')
+			s.end++
+			s.endRunes++
+			continue
+		}
 		if r < 0x0020 && r != '\t' {
-			return s.makeError(`Invalid character within String: "\u%04d".`, r)
+			return s.makeError(`Invalid character within String: "\u%04x".`, r)
 		}
 		switch r {
 		default:
@@ -342,6 +489,7 @@ func (s *Lexer) readString() (Token, *gqlerror.Error) {
 			if buf != nil {
 				t.Value = buf.String()
 			}
+			t.Raw = s.Input[s.start-1 : s.end+1]
 
 			// skip the close quote
 			s.end++
@@ -425,11 +573,12 @@ func (s *Lexer) readBlockString() (Token, *gqlerror.Error) {
 
 		// Closing triple quote (""")
 		if r == '"' && s.end+3 <= inputLen && s.Input[s.end:s.end+3] == `"""` {
-			t, err := s.makeValueToken(BlockString, blockStringValue(buf.String()))
+			t, err := s.makeValueToken(BlockString, BlockStringValue(buf.String()))
 
 			// the token should not include the quotes in its value, but should cover them in its position
 			t.Pos.Start -= 3
 			t.Pos.End += 3
+			t.Raw = s.Input[s.start-3 : s.end+3]
 
 			// skip the close quote
 			s.end += 3
@@ -439,8 +588,14 @@ func (s *Lexer) readBlockString() (Token, *gqlerror.Error) {
 		}
 
 		// SourceCharacter
+		if s.lenient && (r == '\v' || r == '\f') {
+			buf.WriteByte(' ')
+			s.end++
+			s.endRunes++
+			continue
+		}
 		if r < 0x0020 && r != '\t' && r != '\n' && r != '\r' {
-			return s.makeError(`Invalid character within String: "\u%04d".`, r)
+			return s.makeError(`Invalid character within String: "\u%04x".`, r)
 		}
 
 		if r == '\\' && s.end+4 <= inputLen && s.Input[s.end:s.end+4] == `\"""` {
@@ -494,17 +649,18 @@ func unhex(b string) (v rune, ok bool) {
 // readName from the input
 //
 // [_A-Za-z][_0-9A-Za-z]*
+//
+// Names are ASCII only, so the input is read a byte at a time rather than decoding runes. Any other byte,
+// including the first byte of a multi-byte rune, ends the name and is left for the next token.
 func (s *Lexer) readName() (Token, *gqlerror.Error) {
-	for s.end < len(s.Input) {
-		r, w := s.peek()
-
-		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '_' {
-			s.end += w
-			s.endRunes++
-		} else {
-			break
-		}
+	for s.end < len(s.Input) && isNameByte(s.Input[s.end]) {
+		s.end++
+		s.endRunes++
 	}
 
 	return s.makeToken(Name)
 }
+
+func isNameByte(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || b == '_'
+}
@@ -1,10 +1,15 @@
 package lexer
 
 import (
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
 	"github.com/dgraph-io/gqlparser/v2/parser/testrunner"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLexer(t *testing.T) {
@@ -38,3 +43,365 @@ func TestLexer(t *testing.T) {
 		return ret
 	})
 }
+
+func TestLenientStrings(t *testing.T) {
+	readAll := func(input string, opts ...LexerOption) ([]Token, error) {
+		l := New(&ast.Source{Input: input, Name: "spec"}, opts...)
+		var tokens []Token
+		for {
+			tok, err := l.ReadToken()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Kind == EOF {
+				return tokens, nil
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		kind  Type
+		value string
+	}{
+		{"vertical tab in string", "\"a\vb\"", String, "a b"},
+		{"form feed in string", "\"a\fb\\nc\"", String, "a b\nc"},
+		{"vertical tab in block string", "\"\"\"a\vb\"\"\"", BlockString, "a b"},
+		{"form feed in block string", "\"\"\"\n  a\fb\n\"\"\"", BlockString, "a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readAll(tt.input)
+			if err == nil {
+				t.Fatal("expected strict mode to reject the input")
+			}
+
+			tokens, err := readAll(tt.input, WithLenientStrings())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tokens) != 1 || tokens[0].Kind != tt.kind || tokens[0].Value != tt.value {
+				t.Fatalf("unexpected tokens: %v", tokens)
+			}
+			if tokens[0].Pos.End != len(tt.input) {
+				t.Errorf("expected token to end at %d, got %d", len(tt.input), tokens[0].Pos.End)
+			}
+		})
+	}
+
+	t.Run("other control characters are still rejected", func(t *testing.T) {
+		if _, err := readAll("\"a\u0007b\"", WithLenientStrings()); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestSourceLineColumnMatchesLexer(t *testing.T) {
+	src := &ast.Source{Input: "query { # café\n  a(b: \"日本\", c: \"é\")\r\n  d\r  e\r\n\n  \"\"\"日本\"\"\" f }"}
+	l := New(src)
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		line, col := src.LineColumn(tok.Pos.Start)
+		// the position of a string covers its quotes, but the column is that of its value
+		switch tok.Kind {
+		case String:
+			col += len(`"`)
+		case BlockString:
+			col += len(`"""`)
+		}
+		if line != tok.Pos.Line || col != tok.Pos.Column {
+			t.Fatalf("%s at %d:%d, LineColumn(%d) is %d:%d", tok.String(), tok.Pos.Line, tok.Pos.Column, tok.Pos.Start, line, col)
+		}
+		if tok.Kind == EOF {
+			break
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	l := New(&ast.Source{Input: "# a\n# b\nfoo # trailing\n\n# c\nbar"}, WithComments())
+
+	var comments [][]string
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []string
+		for _, comment := range l.Comments() {
+			values = append(values, comment.Value)
+		}
+		comments = append(comments, values)
+		if tok.Kind == EOF {
+			break
+		}
+	}
+	require.Equal(t, [][]string{{"# a", "# b"}, {"# c"}, nil}, comments)
+
+	l = New(&ast.Source{Input: "# a\nfoo"})
+	if _, err := l.ReadToken(); err != nil {
+		t.Fatal(err)
+	}
+	require.Empty(t, l.Comments())
+}
+
+func TestReset(t *testing.T) {
+	src := &ast.Source{Input: "{ a }\n{ b }", Name: "spec"}
+	l := New(src, WithLenientStrings())
+	var first Token
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind == EOF {
+			break
+		}
+		if first.Kind == Invalid {
+			first = tok
+		}
+	}
+
+	l.Reset("\"c\vd\"")
+	tok, err := l.ReadToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Kind != String || tok.Value != "c d" {
+		t.Fatalf("unexpected token %v", tok)
+	}
+	if tok.Pos.Src.Name != "spec" || tok.Pos.Src.Input != "\"c\vd\"" {
+		t.Fatalf("source was not renamed: %+v", tok.Pos.Src)
+	}
+	// the source given to New, and the tokens read from it, still have the first input
+	if line, _ := first.Pos.Snippet(); src.Input != "{ a }\n{ b }" || first.Pos.Src != src || line != "{ a }" {
+		t.Fatalf("source given to New was changed: %+v", src)
+	}
+	fresh := New(&ast.Source{Input: "\"c\vd\""}, WithLenientStrings())
+	expected, _ := fresh.ReadToken()
+	if tok.Pos.Start != expected.Pos.Start || tok.Pos.Line != expected.Pos.Line || tok.Pos.Column != expected.Pos.Column {
+		t.Fatalf("position was not reset: %+v", tok.Pos)
+	}
+}
+
+var smallQueries = []string{
+	"{ me { id name } }",
+	"query Q($id: ID!) { user(id: $id) { name } }",
+	"mutation { like(story: 123) { story { likeCount } } }",
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := New(&ast.Source{Input: smallQueries[i%len(smallQueries)]})
+		readAll(b, &l)
+	}
+}
+
+func BenchmarkReset(b *testing.B) {
+	b.ReportAllocs()
+	var l Lexer
+	for i := 0; i < b.N; i++ {
+		l.Reset(smallQueries[i%len(smallQueries)])
+		readAll(b, &l)
+	}
+}
+
+func BenchmarkReadName(b *testing.B) {
+	// a large schema is mostly names
+	var sb strings.Builder
+	for i := 0; i < 500; i++ {
+		sb.WriteString("type LongObjectTypeName_" + strconv.Itoa(i) + " implements SomeInterface & OtherInterface {\n")
+		sb.WriteString("  someFieldName(firstArgument: InputTypeName, secondArgument: [ID!]): ResultTypeName\n")
+		sb.WriteString("  anotherFieldName: [AnotherTypeName!]! @deprecated\n}\n")
+	}
+	input := sb.String()
+
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := New(&ast.Source{Input: input})
+		readAll(b, &l)
+	}
+}
+
+func readAll(b *testing.B, l *Lexer) {
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if tok.Kind == EOF {
+			return
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	// "Café" then a quote in latin-1, and "Café – €5" in Windows-1252
+	l := New(&ast.Source{Name: "latin1.graphql", Input: "\"Caf\xe9\" \"\xab\xbb\""}, WithEncoding(Latin1))
+	tok, err := l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, "Café", tok.Value)
+	require.Equal(t, "latin1.graphql", tok.Pos.Src.Name)
+	tok, err = l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, "«»", tok.Value)
+	require.Equal(t, 7, tok.Pos.Start)
+	require.Equal(t, "\"«»\"", tok.Pos.Src.Input[8:14])
+
+	l = New(&ast.Source{Input: "\"Caf\xe9 \x96 \x805\""}, WithEncoding(Windows1252))
+	tok, err = l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, "Café – €5", tok.Value)
+
+	l.Reset("\"\x93x\x94\"")
+	tok, err = l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, "“x”", tok.Value)
+
+	l = New(&ast.Source{Input: "\"Caf\xe9\""})
+	tok, err = l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, "Caf\xe9", tok.Value)
+}
+
+func TestRawStrings(t *testing.T) {
+	l := New(&ast.Source{Input: `"plain" "a\"bé\n" """
+    block \""" é
+  """ name`})
+
+	var tokens []Token
+	for {
+		tok, err := l.ReadToken()
+		require.Nil(t, err)
+		if tok.Kind == EOF {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	require.Len(t, tokens, 4)
+
+	require.Equal(t, "plain", tokens[0].Value)
+	require.Equal(t, `"plain"`, tokens[0].Raw)
+
+	require.Equal(t, "a\"bé\n", tokens[1].Value)
+	require.Equal(t, `"a\"bé\n"`, tokens[1].Raw)
+
+	require.Equal(t, `block """ é`, tokens[2].Value)
+	require.Equal(t, "\"\"\"\n    block \\\"\"\" é\n  \"\"\"", tokens[2].Raw)
+
+	require.Equal(t, "name", tokens[3].Value)
+	require.Equal(t, "", tokens[3].Raw)
+
+	// positions cover the raw text, in runes
+	input := []rune(l.Input)
+	for _, tok := range tokens[:3] {
+		require.Equal(t, tok.Raw, string(input[tok.Pos.Start:tok.Pos.End]))
+	}
+}
+
+func TestBlankLineBefore(t *testing.T) {
+	l := New(&ast.Source{Input: "a b\nc\n\nd\n  \t\r\ne\n# comment\nf\n\n# comment\ng\n# comment\n\nh"})
+
+	blank := map[string]bool{}
+	for {
+		tok, err := l.ReadToken()
+		require.Nil(t, err)
+		if tok.Kind == EOF {
+			break
+		}
+		blank[tok.Value] = tok.BlankLineBefore
+	}
+
+	require.Equal(t, map[string]bool{
+		"a": false,
+		"b": false,
+		"c": false,
+		"d": true,
+		"e": true,
+		"f": false,
+		"g": true,
+		"h": true,
+	}, blank)
+}
+
+func TestLongCommentRuns(t *testing.T) {
+	input := strings.Repeat("# a comment line\n", 100000) + "name # trailing\n" + strings.Repeat("#\n", 100000)
+
+	l := New(&ast.Source{Input: input}, WithComments())
+	tok, err := l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, Name, tok.Kind)
+	require.Equal(t, "name", tok.Value)
+	require.Equal(t, 100001, tok.Pos.Line)
+	require.Len(t, l.Comments(), 100000)
+	require.Equal(t, "# a comment line", l.Comments()[99999].Value)
+
+	tok, err = l.ReadToken()
+	require.Nil(t, err)
+	require.Equal(t, EOF, tok.Kind)
+	require.Len(t, l.Comments(), 100000)
+}
+
+func TestNumberLength(t *testing.T) {
+	l := New(&ast.Source{Input: "{ a(n: " + strings.Repeat("1", maxNumberLength) + "e1) }", Name: "spec"})
+	var err *gqlerror.Error
+	for err == nil {
+		var tok Token
+		tok, err = l.ReadToken()
+		require.NotEqual(t, EOF, tok.Kind)
+	}
+	require.Equal(t, "spec:1:8: Invalid number, longer than 1000 characters.", err.Error())
+	require.Equal(t, 8, err.Locations[0].Column)
+}
+
+func FuzzNumbers(f *testing.F) {
+	for _, seed := range []string{
+		"1e999999999",
+		"-1e-999999999",
+		"1.5E+308 1.8e308",
+		"-0",
+		"-0.0e0",
+		"9223372036854775808",
+		strings.Repeat("1", maxNumberLength),
+		strings.Repeat("1", maxNumberLength+1),
+		"0." + strings.Repeat("0", 500) + "1e" + strings.Repeat("9", 400),
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		l := New(&ast.Source{Input: input})
+		// every token consumes input, so this is more reads than can succeed
+		for i := 0; i <= len(input); i++ {
+			tok, err := l.ReadToken()
+			if err != nil || tok.Kind == EOF {
+				return
+			}
+
+			var kind ast.ValueKind
+			switch tok.Kind {
+			case Int:
+				kind = ast.IntValue
+			case Float:
+				kind = ast.FloatValue
+			default:
+				continue
+			}
+			if len(tok.Value) > maxNumberLength {
+				t.Fatalf("%d character number %s lexed", len(tok.Value), tok.Value)
+			}
+			value, verr := (&ast.Value{Kind: kind, Raw: tok.Value}).Value(nil)
+			if f, ok := value.(float64); ok && verr == nil && (math.IsInf(f, 0) || math.IsNaN(f)) {
+				t.Fatalf("%s converted to %v", tok.Value, f)
+			}
+		}
+		t.Fatalf("lexer made no progress on %q", input)
+	})
+}
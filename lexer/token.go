@@ -138,11 +138,28 @@ type Token struct {
 	Kind  Type         // The token type.
 	Value string       // The literal value consumed.
 	Pos   ast.Position // The file and line this token was read from
+	// Raw is the source text of String and BlockString tokens, with their quotes and escape sequences, as Value
+	// holds the decoded string. It is empty for other tokens.
+	Raw string
+	// BlankLineBefore is set when there is an empty line, or a line with only whitespace, between the previous
+	// token and this one. Comments don't count as empty lines.
+	BlankLineBefore bool
 }
 
+// String describes the token as it appears in parser errors, eg `Name "foo"`.
 func (t Token) String() string {
 	if t.Value != "" {
 		return t.Kind.String() + " " + strconv.Quote(t.Value)
 	}
 	return t.Kind.String()
 }
+
+// GoString describes the token with its kind name and position for debugging, eg `Name "foo" at schema:1:6`. It
+// is used by the %#v verb and so in test failures.
+func (t Token) GoString() string {
+	s := t.Kind.Name()
+	if t.Value != "" {
+		s += " " + strconv.Quote(t.Value)
+	}
+	return s + " at " + t.Pos.String()
+}
@@ -0,0 +1,38 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeNames(t *testing.T) {
+	for typ := Invalid; typ <= Comment; typ++ {
+		require.NotEmpty(t, typ.Name(), "type %d", int(typ))
+		require.False(t, strings.HasPrefix(typ.Name(), "Unknown"), "type %d has no name", int(typ))
+		require.False(t, strings.HasPrefix(typ.String(), "Unknown"), "type %d has no string", int(typ))
+	}
+	require.Equal(t, "Unknown 99", Type(99).Name())
+}
+
+func TestTokenString(t *testing.T) {
+	l := New(&ast.Source{Name: "schema", Input: "type Foo!"})
+	var tokens []Token
+	for {
+		tok, err := l.ReadToken()
+		require.Nil(t, err)
+		tokens = append(tokens, tok)
+		if tok.Kind == EOF {
+			break
+		}
+	}
+
+	require.Equal(t, `Name "Foo"`, tokens[1].String())
+	require.Equal(t, "!", tokens[2].String())
+	require.Equal(t, `Name "Foo" at schema:1:6`, fmt.Sprintf("%#v", tokens[1]))
+	require.Equal(t, "Bang at schema:1:9", fmt.Sprintf("%#v", tokens[2]))
+	require.Equal(t, "EOF at schema:1:10", fmt.Sprintf("%#v", tokens[3]))
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
 	"github.com/dgraph-io/gqlparser/v2/gqlerror"
@@ -10,13 +11,24 @@ import (
 
 type parser struct {
 	lexer lexer.Lexer
-	err   *gqlerror.Error
+	// when set, tokens are read from here instead of the lexer. The last token must be an EOF.
+	tokens []lexer.Token
+	err    *gqlerror.Error
 
 	peeked    bool
 	peekToken lexer.Token
 	peekError *gqlerror.Error
+	// the comments before peekToken, when the lexer keeps them
+	peekComments []lexer.Token
 
 	prev lexer.Token
+
+	// set while parsing a default value, to explain why variables aren't allowed
+	inDefaultValue bool
+
+	// counters for the WithStats functions, nil unless asked for, and the current nesting for MaxDepth
+	stats *ParseStats
+	depth int
 }
 
 func (p *parser) peekPos() *ast.Position {
@@ -24,8 +36,26 @@ func (p *parser) peekPos() *ast.Position {
 		return nil
 	}
 
-	peek := p.peek()
-	return &peek.Pos
+	return p.tokenPos(p.peek())
+}
+
+// tokenPos returns the position of tok for a new ast node, or nil if the lexer was created WithoutLocations. Every
+// node takes its position from here as it is built, so this is also where nodes are counted.
+func (p *parser) tokenPos(tok lexer.Token) *ast.Position {
+	if p.stats != nil {
+		p.stats.Nodes++
+	}
+	return p.position(tok)
+}
+
+// position is tokenPos for a node that was already counted.
+func (p *parser) position(tok lexer.Token) *ast.Position {
+	if !p.lexer.Locations() {
+		return nil
+	}
+	// copying the position, rather than taking the address of tok's, only moves the position to the heap
+	pos := tok.Pos
+	return &pos
 }
 
 func (p *parser) peek() lexer.Token {
@@ -34,7 +64,18 @@ func (p *parser) peek() lexer.Token {
 	}
 
 	if !p.peeked {
-		p.peekToken, p.peekError = p.lexer.ReadToken()
+		if p.tokens != nil {
+			p.peekToken = p.tokens[0]
+			if len(p.tokens) > 1 {
+				p.tokens = p.tokens[1:]
+			}
+		} else {
+			p.peekToken, p.peekError = p.lexer.ReadToken()
+			p.peekComments = p.lexer.Comments()
+			if p.stats != nil && p.peekError == nil {
+				p.countToken(p.peekToken)
+			}
+		}
 		p.peeked = true
 	}
 
@@ -45,7 +86,7 @@ func (p *parser) error(tok lexer.Token, format string, args ...interface{}) {
 	if p.err != nil {
 		return
 	}
-	p.err = gqlerror.ErrorLocf(tok.Pos.Src.Name, tok.Pos.Line, tok.Pos.Column, format, args...)
+	p.err = gqlerror.ErrorPosf(&tok.Pos, format, args...)
 }
 
 func (p *parser) next() lexer.Token {
@@ -67,6 +108,11 @@ func (p *parser) expectKeyword(value string) lexer.Token {
 		return p.next()
 	}
 
+	if matchKeyword(tok, value) != "" {
+		p.keywordCaseError(tok, value)
+		return tok
+	}
+
 	p.error(tok, "Expected %s, found %s", strconv.Quote(value), tok.String())
 	return tok
 }
@@ -103,6 +149,41 @@ func (p *parser) unexpectedToken(tok lexer.Token) {
 	p.error(tok, "Unexpected %s", tok.String())
 }
 
+// unexpectedAfterDocument reports a token that can't start a definition following a complete one, usually stray
+// text after the end of the document.
+func (p *parser) unexpectedAfterDocument() {
+	tok := p.peek()
+	p.error(tok, "Unexpected %s after document.", tok.String())
+}
+
+// unexpectedKeyword reports the next token as unexpected where one of keywords was expected. Keywords are case
+// sensitive, so a name that only differs in case from one of them gets a hint.
+func (p *parser) unexpectedKeyword(keywords ...string) {
+	tok := p.peek()
+	if keyword := matchKeyword(tok, keywords...); keyword != "" {
+		p.keywordCaseError(tok, keyword)
+		return
+	}
+	p.unexpectedToken(tok)
+}
+
+func (p *parser) keywordCaseError(tok lexer.Token, keyword string) {
+	p.error(tok, "Unexpected %s; did you mean %s?", tok.String(), strconv.Quote(keyword))
+}
+
+// matchKeyword returns the keyword tok is a name for, ignoring case, or "" if there is none.
+func matchKeyword(tok lexer.Token, keywords ...string) string {
+	if tok.Kind != lexer.Name {
+		return ""
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(tok.Value, keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
+
 func (p *parser) many(start lexer.Type, end lexer.Type, cb func()) {
 	hasDef := p.skip(start)
 	if !hasDef {
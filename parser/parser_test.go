@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
@@ -64,7 +65,7 @@ func TestParserUtils(t *testing.T) {
 					p.error(p.peek(), "boom")
 				}
 			})
-			require.EqualError(t, p.err, "input.graphql:1: boom")
+			require.EqualError(t, p.err, "input.graphql:1:6: boom")
 			require.Equal(t, []string{"a", "b"}, arr)
 		})
 	})
@@ -91,7 +92,7 @@ func TestParserUtils(t *testing.T) {
 			p.some(lexer.BracketL, lexer.BracketR, func() {
 				arr = append(arr, p.next().Value)
 			})
-			require.EqualError(t, p.err, "input.graphql:1: expected at least one definition, found ]")
+			require.EqualError(t, p.err, "input.graphql:1:2: expected at least one definition, found ]")
 			require.Equal(t, []string(nil), arr)
 			require.NotEqual(t, lexer.EOF, p.peek().Kind)
 		})
@@ -116,7 +117,7 @@ func TestParserUtils(t *testing.T) {
 					p.error(p.peek(), "boom")
 				}
 			})
-			require.EqualError(t, p.err, "input.graphql:1: boom")
+			require.EqualError(t, p.err, "input.graphql:1:6: boom")
 			require.Equal(t, []string{"a", "b"}, arr)
 		})
 	})
@@ -128,7 +129,7 @@ func TestParserUtils(t *testing.T) {
 		p.error(p.peek(), "test error")
 		p.error(p.peek(), "secondary error")
 
-		require.EqualError(t, p.err, "input.graphql:1: test error")
+		require.EqualError(t, p.err, "input.graphql:1:5: test error")
 
 		require.Equal(t, "foo", p.peek().Value)
 		require.Equal(t, "foo", p.next().Value)
@@ -138,30 +139,168 @@ func TestParserUtils(t *testing.T) {
 	t.Run("unexpected error", func(t *testing.T) {
 		p := newParser("1 3")
 		p.unexpectedError()
-		require.EqualError(t, p.err, "input.graphql:1: Unexpected Int \"1\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Unexpected Int \"1\"")
 	})
 
 	t.Run("unexpected error", func(t *testing.T) {
 		p := newParser("1 3")
 		p.unexpectedToken(p.next())
-		require.EqualError(t, p.err, "input.graphql:1: Unexpected Int \"1\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Unexpected Int \"1\"")
 	})
 
 	t.Run("expect error", func(t *testing.T) {
 		p := newParser("foo bar")
 		p.expect(lexer.Float)
 
-		require.EqualError(t, p.err, "input.graphql:1: Expected Float, found Name")
+		require.EqualError(t, p.err, "input.graphql:1:1: Expected Float, found Name")
 	})
 
 	t.Run("expectKeyword error", func(t *testing.T) {
 		p := newParser("foo bar")
 		p.expectKeyword("baz")
 
-		require.EqualError(t, p.err, "input.graphql:1: Expected \"baz\", found Name \"foo\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Expected \"baz\", found Name \"foo\"")
 	})
 }
 
 func newParser(input string) parser {
 	return parser{lexer: lexer.New(&ast.Source{Input: input, Name: "input.graphql"})}
 }
+
+func TestMustParse(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		doc := MustParseQuery(&ast.Source{Input: "{ name }"})
+		require.Equal(t, "name", doc.Operations[0].SelectionSet[0].(*ast.Field).Name)
+
+		require.Equal(t, "query.graphql:1:3: expected at least one definition, found }", panicError(func() {
+			MustParseQuery(&ast.Source{Name: "query.graphql", Input: "{ }"})
+		}))
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		doc := MustParseSchema(&ast.Source{Input: "type Query { name: String }"})
+		require.Equal(t, "Query", doc.Definitions[0].Name)
+
+		require.Equal(t, "schema.graphql:1:19: Expected :, found }", panicError(func() {
+			MustParseSchema(&ast.Source{Name: "schema.graphql", Input: "type Query { name }"})
+		}))
+	})
+}
+
+// panicError returns the message of the error f panics with, or an empty string if it doesn't panic.
+func panicError(f func()) (message string) {
+	defer func() {
+		if err, ok := recover().(error); ok {
+			message = err.Error()
+		}
+	}()
+	f()
+	return ""
+}
+
+func TestParseWithStats(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		_, stats, err := ParseQueryWithStats(&ast.Source{Input: "# a comment\nquery Q($a: Int) { user(id: $a) { name } }"})
+		require.Nil(t, err)
+
+		// document, operation, variable, its type, two fields, the argument and its value
+		require.Equal(t, ParseStats{Tokens: 20, Nodes: 8, MaxDepth: 2}, stats)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		_, stats, err := ParseSchemaWithStats(&ast.Source{Input: `type Query { users(ids: [[ID!]]): [User] }`})
+		require.Nil(t, err)
+
+		// document, definition, field, argument, and the types [[ID!]], [ID!], ID! and [User], User
+		require.Equal(t, ParseStats{Tokens: 19, Nodes: 9, MaxDepth: 4}, stats)
+	})
+
+	t.Run("counted without locations", func(t *testing.T) {
+		_, stats, err := ParseQueryWithStats(&ast.Source{Input: "{ user { name } }"}, lexer.WithoutLocations())
+		require.Nil(t, err)
+		require.Equal(t, ParseStats{Tokens: 6, Nodes: 4, MaxDepth: 2}, stats)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, stats, err := ParseQueryWithStats(&ast.Source{Input: "{ user( }"})
+		require.NotNil(t, err)
+
+		// the document, operation, field and the argument that has the error were started
+		require.Equal(t, ParseStats{Tokens: 4, Nodes: 4, MaxDepth: 2}, stats)
+	})
+}
+
+func TestWithoutLocations(t *testing.T) {
+	// every node that has a Position, whether it was left nil
+	positions := func(doc interface{}) map[string]bool {
+		nilPositions := map[string]bool{}
+		ast.Apply(doc, func(node interface{}) (interface{}, bool) {
+			if pos := reflect.ValueOf(node).Elem().FieldByName("Position"); pos.IsValid() {
+				name := reflect.TypeOf(node).Elem().Name()
+				nilPositions[name] = pos.IsNil()
+			}
+			return node, false
+		})
+		return nilPositions
+	}
+
+	query := `query Q($id: ID = 1) @a { user(id: $id, filter: {tags: ["a"]}) { ...F ... on User { name } } }
+		fragment F on User { id }`
+	doc, err := ParseQuery(&ast.Source{Input: query}, lexer.WithoutLocations())
+	require.Nil(t, err)
+	require.Equal(t, map[string]bool{
+		"QueryDocument": true, "OperationDefinition": true, "VariableDefinition": true, "Type": true, "Value": true,
+		"Directive": true, "Field": true, "Argument": true, "FragmentSpread": true, "InlineFragment": true,
+		"FragmentDefinition": true,
+	}, positions(doc))
+	require.Nil(t, doc.Operations[0].SelectionSet[0].(*ast.Field).Arguments[1].Value.Children[0].Position)
+
+	doc, err = ParseQuery(&ast.Source{Input: query})
+	require.Nil(t, err)
+	for name, isNil := range positions(doc) {
+		require.False(t, isNil, name)
+	}
+
+	schema, err := ParseSchema(&ast.Source{Input: `
+		schema { query: Query }
+		directive @a(x: Int = 1) on OBJECT
+		type Query @a { user(id: ID): User }
+		enum Role { ADMIN }
+		extend type Query { role: Role }
+	`}, lexer.WithoutLocations())
+	require.Nil(t, err)
+	require.Equal(t, map[string]bool{
+		"SchemaDocument": true, "SchemaDefinition": true, "OperationTypeDefinition": true,
+		"DirectiveDefinition": true, "ArgumentDefinition": true, "Type": true, "Value": true, "Definition": true,
+		"Directive": true, "FieldDefinition": true, "EnumValueDefinition": true,
+	}, positions(schema))
+
+	// parse errors are still located
+	_, err = ParseQuery(&ast.Source{Name: "query.graphql", Input: "{\n  user(id: ) }"}, lexer.WithoutLocations())
+	require.NotNil(t, err)
+	require.Equal(t, "query.graphql:2:12: Unexpected )", err.Error())
+}
+
+func BenchmarkParseQuery(b *testing.B) {
+	src := &ast.Source{Input: `query Q($id: ID!, $first: Int = 10) {
+		user(id: $id) { id name friends(first: $first, filter: {tags: ["a", "b"]}) { ...F ... on User { email } } }
+	}
+	fragment F on User { id name avatar(size: 64) }`}
+
+	for _, bench := range []struct {
+		name string
+		opts []lexer.LexerOption
+	}{
+		{"with locations", nil},
+		{"without locations", []lexer.LexerOption{lexer.WithoutLocations()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseQuery(src, bench.opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -7,15 +7,54 @@ import (
 	. "github.com/dgraph-io/gqlparser/v2/ast"
 )
 
-func ParseQuery(source *Source) (*QueryDocument, *gqlerror.Error) {
+// ParseQuery parses an executable document. It must have at least one definition, so input that is empty or only
+// has whitespace and comments is an error.
+func ParseQuery(source *Source, opts ...lexer.LexerOption) (*QueryDocument, *gqlerror.Error) {
+	return parseQuery(source, nil, opts...)
+}
+
+func parseQuery(source *Source, stats *ParseStats, opts ...lexer.LexerOption) (*QueryDocument, *gqlerror.Error) {
+	p := parser{
+		lexer: lexer.New(source, opts...),
+		stats: stats,
+	}
+	doc := p.parseQueryDocument()
+	return doc, p.err
+}
+
+// MustParseQuery is ParseQuery for trusted input, such as queries embedded in tests and tools, it panics with
+// the error if the query can't be parsed.
+func MustParseQuery(source *Source, opts ...lexer.LexerOption) *QueryDocument {
+	doc, err := ParseQuery(source, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+// ParseValue parses a single constant value literal, eg a default value as printed by introspection.
+func ParseValue(source *Source) (*Value, *gqlerror.Error) {
 	p := parser{
 		lexer: lexer.New(source),
 	}
-	return p.parseQueryDocument(), p.err
+	value := p.parseValueLiteral(true)
+	if p.err == nil && p.peek().Kind != lexer.EOF {
+		p.unexpectedError()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return value, nil
 }
 
 func (p *parser) parseQueryDocument() *QueryDocument {
 	var doc QueryDocument
+	// a document must have at least one definition, the lexer has already skipped any byte order mark,
+	// whitespace and comments
+	if tok := p.peek(); tok.Kind == lexer.EOF && p.err == nil {
+		p.error(tok, "Expected a definition but found %s.", tok.Kind.String())
+		return &doc
+	}
 	for p.peek().Kind != lexer.EOF {
 		if p.err != nil {
 			return &doc
@@ -29,11 +68,19 @@ func (p *parser) parseQueryDocument() *QueryDocument {
 			case "fragment":
 				doc.Fragments = append(doc.Fragments, p.parseFragmentDefinition())
 			default:
-				p.unexpectedError()
+				if keyword := matchKeyword(p.peek(), "query", "mutation", "subscription", "fragment"); keyword != "" {
+					p.keywordCaseError(p.peek(), keyword)
+					break
+				}
+				p.error(p.peek(), `Expected "{" or operation type, found %s`, p.peek().Kind.String())
 			}
 		case lexer.BraceL:
 			doc.Operations = append(doc.Operations, p.parseOperationDefinition())
 		default:
+			if len(doc.Operations) != 0 || len(doc.Fragments) != 0 {
+				p.unexpectedAfterDocument()
+				break
+			}
 			p.unexpectedError()
 		}
 	}
@@ -98,10 +145,10 @@ func (p *parser) parseVariableDefinition() *VariableDefinition {
 	def.Type = p.parseTypeReference()
 
 	if p.skip(lexer.Equals) {
-		def.DefaultValue = p.parseValueLiteral(true)
+		def.DefaultValue = p.parseDefaultValue()
 	}
 
-	def.Directives = p.parseDirectives(false)
+	def.Directives = p.parseDirectives(true)
 
 	return &def
 }
@@ -229,6 +276,13 @@ func (p *parser) parseFragmentName() string {
 	return p.parseName()
 }
 
+// parseDefaultValue parses the default value of a variable, argument or input field, which must be constant.
+func (p *parser) parseDefaultValue() *Value {
+	p.inDefaultValue = true
+	defer func() { p.inDefaultValue = false }()
+	return p.parseValueLiteral(true)
+}
+
 func (p *parser) parseValueLiteral(isConst bool) *Value {
 	token := p.peek()
 
@@ -239,11 +293,21 @@ func (p *parser) parseValueLiteral(isConst bool) *Value {
 	case lexer.BraceL:
 		return p.parseObject(isConst)
 	case lexer.Dollar:
+		if isConst && p.inDefaultValue {
+			p.next()
+			name := p.peek()
+			if name.Kind == lexer.Name {
+				p.error(token, `Variable "$%s" is not allowed in a default value.`, name.Value)
+				return nil
+			}
+			p.unexpectedToken(token)
+			return nil
+		}
 		if isConst {
 			p.unexpectedError()
 			return nil
 		}
-		return &Value{Position: &token.Pos, Raw: p.parseVariable(), Kind: Variable}
+		return &Value{Position: p.tokenPos(token), Raw: p.parseVariable(), Kind: Variable}
 	case lexer.Int:
 		kind = IntValue
 	case lexer.Float:
@@ -268,14 +332,14 @@ func (p *parser) parseValueLiteral(isConst bool) *Value {
 
 	p.next()
 
-	return &Value{Position: &token.Pos, Raw: token.Value, Kind: kind}
+	return &Value{Position: p.tokenPos(token), Raw: token.Value, Kind: kind}
 }
 
 func (p *parser) parseList(isConst bool) *Value {
 	var values ChildValueList
 	pos := p.peekPos()
 	p.many(lexer.BracketL, lexer.BracketR, func() {
-		values = append(values, &ChildValue{Value: p.parseValueLiteral(isConst)})
+		values = append(values, &ChildValue{Position: p.peekPos(), Value: p.parseValueLiteral(isConst)})
 	})
 
 	return &Value{Children: values, Kind: ListValue, Position: pos}
@@ -337,7 +401,8 @@ func (p *parser) parseTypeReference() *Type {
 	}
 
 	if p.skip(lexer.Bang) {
-		typ.Position = p.peekPos()
+		// a non-null type is the same node, only its position moves to the bang
+		typ.Position = p.position(p.peek())
 		typ.NonNull = true
 	}
 	return &typ
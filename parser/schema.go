@@ -1,14 +1,24 @@
 package parser
 
 import (
+	"strings"
+
 	. "github.com/dgraph-io/gqlparser/v2/ast"
 	"github.com/dgraph-io/gqlparser/v2/gqlerror"
 	"github.com/dgraph-io/gqlparser/v2/lexer"
 )
 
-func ParseSchema(source *Source) (*SchemaDocument, *gqlerror.Error) {
+// ParseSchema parses a schema document. With lexer.WithComments, the comments right above a definition, field,
+// argument or enum value without a description are used as its description, as legacy schemas did. Comments in
+// built in sources, such as the prelude, are only notes and never become descriptions.
+func ParseSchema(source *Source, opts ...lexer.LexerOption) (*SchemaDocument, *gqlerror.Error) {
+	return parseSchema(source, nil, opts...)
+}
+
+func parseSchema(source *Source, stats *ParseStats, opts ...lexer.LexerOption) (*SchemaDocument, *gqlerror.Error) {
 	p := parser{
-		lexer: lexer.New(source),
+		lexer: lexer.New(source, opts...),
+		stats: stats,
 	}
 	ast, err := p.parseSchemaDocument(), p.err
 	if err != nil {
@@ -21,54 +31,122 @@ func ParseSchema(source *Source) (*SchemaDocument, *gqlerror.Error) {
 	for _, def := range ast.Extensions {
 		def.BuiltIn = source.BuiltIn
 	}
+	for _, def := range ast.Directives {
+		def.BuiltIn = source.BuiltIn
+	}
 
 	return ast, nil
 }
 
+// MustParseSchema is ParseSchema for trusted input, such as schemas embedded in tests and tools, it panics with
+// the error if the schema can't be parsed.
+func MustParseSchema(source *Source, opts ...lexer.LexerOption) *SchemaDocument {
+	doc, err := ParseSchema(source, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+// ParseSchemas parses each source and merges them into one document. A type or directive defined in more than
+// one source is an error located at both definitions, duplicates within a single source are left to the
+// validator. Extensions are merged as they are and never conflict with their base type.
 func ParseSchemas(inputs ...*Source) (*SchemaDocument, *gqlerror.Error) {
+	return ParseSchemasWithOptions(inputs)
+}
+
+// ParseSchemasWithOptions is ParseSchemas with lexer options applied to every source.
+func ParseSchemasWithOptions(inputs []*Source, opts ...lexer.LexerOption) (*SchemaDocument, *gqlerror.Error) {
 	ast := &SchemaDocument{}
+	types := map[string]*Definition{}
+	directives := map[string]*DirectiveDefinition{}
 	for _, input := range inputs {
-		inputAst, err := ParseSchema(input)
+		inputAst, err := ParseSchema(input, opts...)
 		if err != nil {
 			return nil, err
 		}
+		for _, def := range inputAst.Definitions {
+			if prev := types[def.Name]; prev != nil {
+				return nil, duplicateDefinitionError(def.Position, prev.Position, `Type "%s"`, def.Name)
+			}
+		}
+		for _, def := range inputAst.Directives {
+			if prev := directives[def.Name]; prev != nil {
+				return nil, duplicateDefinitionError(def.Position, prev.Position, `Directive "@%s"`, def.Name)
+			}
+		}
+		for _, def := range inputAst.Definitions {
+			types[def.Name] = def
+		}
+		for _, def := range inputAst.Directives {
+			directives[def.Name] = def
+		}
 		ast.Merge(inputAst)
 	}
 	return ast, nil
 }
 
+// duplicateDefinitionError reports a definition at pos that was already defined at prev, in another source.
+func duplicateDefinitionError(pos, prev *Position, what string, name string) *gqlerror.Error {
+	err := gqlerror.ErrorPosf(pos, what+" defined in %s and %s.", name, sourceName(prev), sourceName(pos))
+	err.Locations = append(err.Locations, gqlerror.Location{Line: prev.Line, Column: prev.Column, Source: sourceName(prev), Start: prev.Start, End: prev.End})
+	return err
+}
+
+func sourceName(pos *Position) string {
+	if pos.Src == nil {
+		return ""
+	}
+	return pos.Src.Name
+}
+
 func (p *parser) parseSchemaDocument() *SchemaDocument {
 	var doc SchemaDocument
 	doc.Position = p.peekPos()
-	for p.peek().Kind != lexer.EOF {
+	// the number of definitions parsed so far, including schema definitions and extensions
+	definitions := 0
+	for ; p.peek().Kind != lexer.EOF; definitions++ {
 		if p.err != nil {
 			return nil
 		}
 
-		var description string
-		if p.peek().Kind == lexer.BlockString || p.peek().Kind == lexer.String {
-			description = p.parseDescription()
-		}
+		blankLine := p.peek().BlankLineBefore
+		hasDescription := p.peek().Kind == lexer.BlockString || p.peek().Kind == lexer.String
+		description := p.parseDescription()
 
 		if p.peek().Kind != lexer.Name {
+			if !hasDescription && definitions > 0 {
+				p.unexpectedAfterDocument()
+				break
+			}
 			p.unexpectedError()
 			break
 		}
 
 		switch p.peek().Value {
 		case "scalar", "type", "interface", "union", "enum", "input":
-			doc.Definitions = append(doc.Definitions, p.parseTypeSystemDefinition(description))
+			def := p.parseTypeSystemDefinition(description)
+			if def != nil {
+				def.LeadingBlankLine = blankLine
+			}
+			doc.Definitions = append(doc.Definitions, def)
 		case "schema":
 			doc.Schema = append(doc.Schema, p.parseSchemaDefinition(description))
 		case "directive":
-			doc.Directives = append(doc.Directives, p.parseDirectiveDefinition(description))
+			def := p.parseDirectiveDefinition(description)
+			def.LeadingBlankLine = blankLine
+			doc.Directives = append(doc.Directives, def)
 		case "extend":
-			if description != "" {
+			if hasDescription {
 				p.unexpectedToken(p.prev)
 			}
+			extensions := len(doc.Extensions)
 			p.parseTypeSystemExtension(&doc)
+			if len(doc.Extensions) > extensions {
+				doc.Extensions[extensions].LeadingBlankLine = blankLine
+			}
 		default:
-			p.unexpectedError()
+			p.unexpectedKeyword("scalar", "type", "interface", "union", "enum", "input", "schema", "directive", "extend")
 			return nil
 		}
 	}
@@ -80,12 +158,49 @@ func (p *parser) parseDescription() string {
 	token := p.peek()
 
 	if token.Kind != lexer.BlockString && token.Kind != lexer.String {
-		return ""
+		return p.commentDescription()
 	}
 
 	return p.next().Value
 }
 
+// commentDescription returns the block of comments on the lines right above the next token, for legacy schemas
+// that document definitions with comments. It's only used when the lexer keeps comments, see lexer.WithComments.
+func (p *parser) commentDescription() string {
+	if len(p.peekComments) == 0 || p.lexer.BuiltIn {
+		return ""
+	}
+	line := p.peek().Pos.Line
+	start := len(p.peekComments)
+	for start > 0 && p.peekComments[start-1].Pos.Line == line-1 {
+		start--
+		line--
+	}
+	if start == len(p.peekComments) {
+		return ""
+	}
+
+	lines := make([]string, 0, len(p.peekComments)-start)
+	indent := -1
+	for _, comment := range p.peekComments[start:] {
+		text := strings.TrimPrefix(comment.Value, "#")
+		lines = append(lines, text)
+		if trimmed := strings.TrimLeft(text, " \t"); trimmed != "" {
+			if n := len(text) - len(trimmed); indent == -1 || n < indent {
+				indent = n
+			}
+		}
+	}
+	for i, text := range lines {
+		if indent > 0 && len(text) >= indent {
+			text = text[indent:]
+		}
+		lines[i] = strings.TrimRight(text, " \t")
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
 func (p *parser) parseTypeSystemDefinition(description string) *Definition {
 	tok := p.peek()
 	if tok.Kind != lexer.Name {
@@ -132,6 +247,10 @@ func (p *parser) parseOperationTypeDefinition() *OperationTypeDefinition {
 	op.Operation = p.parseOperationType()
 	p.expect(lexer.Colon)
 	op.Type = p.parseName()
+	if tok := p.peek(); tok.Kind == lexer.At {
+		// the spec has no directive location for root operation types, only for the schema itself
+		p.error(tok, "Unexpected @, directives are not allowed on root operation types, only on the schema definition")
+	}
 	return &op
 }
 
@@ -144,9 +263,18 @@ func (p *parser) parseScalarTypeDefinition(description string) *Definition {
 	def.Description = description
 	def.Name = p.parseName()
 	def.Directives = p.parseDirectives(true)
+	p.expectNoFields()
 	return &def
 }
 
+// expectNoFields errors on a fields block after a scalar, which would otherwise be reported as an unexpected
+// start of the next definition.
+func (p *parser) expectNoFields() {
+	if tok := p.peek(); tok.Kind == lexer.BraceL {
+		p.error(tok, "Expected directive or end of scalar definition, found %s", tok.Kind.String())
+	}
+}
+
 func (p *parser) parseObjectTypeDefinition(description string) *Definition {
 	p.expectKeyword("type")
 
@@ -187,6 +315,7 @@ func (p *parser) parseFieldsDefinition() FieldList {
 func (p *parser) parseFieldDefinition() *FieldDefinition {
 	var def FieldDefinition
 	def.Position = p.peekPos()
+	def.LeadingBlankLine = p.peek().BlankLineBefore
 	def.Description = p.parseDescription()
 	def.Name = p.parseName()
 	def.Arguments = p.parseArgumentDefs()
@@ -213,7 +342,7 @@ func (p *parser) parseArgumentDef() *ArgumentDefinition {
 	p.expect(lexer.Colon)
 	def.Type = p.parseTypeReference()
 	if p.skip(lexer.Equals) {
-		def.DefaultValue = p.parseValueLiteral(true)
+		def.DefaultValue = p.parseDefaultValue()
 	}
 	def.Directives = p.parseDirectives(true)
 	return &def
@@ -222,12 +351,13 @@ func (p *parser) parseArgumentDef() *ArgumentDefinition {
 func (p *parser) parseInputValueDef() *FieldDefinition {
 	var def FieldDefinition
 	def.Position = p.peekPos()
+	def.LeadingBlankLine = p.peek().BlankLineBefore
 	def.Description = p.parseDescription()
 	def.Name = p.parseName()
 	p.expect(lexer.Colon)
 	def.Type = p.parseTypeReference()
 	if p.skip(lexer.Equals) {
-		def.DefaultValue = p.parseValueLiteral(true)
+		def.DefaultValue = p.parseDefaultValue()
 	}
 	def.Directives = p.parseDirectives(true)
 	return &def
@@ -296,11 +426,26 @@ func (p *parser) parseEnumValuesDefinition() EnumValueList {
 
 func (p *parser) parseEnumValueDefinition() *EnumValueDefinition {
 	return &EnumValueDefinition{
-		Position:    p.peekPos(),
-		Description: p.parseDescription(),
-		Name:        p.parseName(),
-		Directives:  p.parseDirectives(true),
+		Position:         p.peekPos(),
+		LeadingBlankLine: p.peek().BlankLineBefore,
+		Description:      p.parseDescription(),
+		Name:             p.parseEnumValueName(),
+		Directives:       p.parseDirectives(true),
+	}
+}
+
+// parseEnumValueName parses the name of an enum value, which can't be true, false or null as values with those
+// names are always booleans or null.
+func (p *parser) parseEnumValueName() string {
+	tok := p.peek()
+	switch tok.Value {
+	case "true", "false", "null":
+		if tok.Kind == lexer.Name {
+			p.error(tok, "%s is reserved and cannot be used for an enum value.", tok.String())
+			return ""
+		}
 	}
+	return p.parseName()
 }
 
 func (p *parser) parseInputObjectTypeDefinition(description string) *Definition {
@@ -343,7 +488,7 @@ func (p *parser) parseTypeSystemExtension(doc *SchemaDocument) {
 	case "input":
 		doc.Extensions = append(doc.Extensions, p.parseInputObjectTypeExtension())
 	default:
-		p.unexpectedError()
+		p.unexpectedKeyword("schema", "scalar", "type", "interface", "union", "enum", "input")
 	}
 }
 
@@ -373,6 +518,7 @@ func (p *parser) parseScalarTypeExtension() *Definition {
 	if len(def.Directives) == 0 {
 		p.unexpectedError()
 	}
+	p.expectNoFields()
 	return &def
 }
 
@@ -463,6 +609,11 @@ func (p *parser) parseDirectiveDefinition(description string) *DirectiveDefiniti
 	def.Name = p.parseName()
 	def.Arguments = p.parseArgumentDefs()
 
+	if peek := p.peek(); peek.Kind == lexer.Name && peek.Value == "repeatable" {
+		def.IsRepeatable = true
+		p.next()
+	}
+
 	p.expectKeyword("on")
 	def.Locations = p.parseDirectiveLocations()
 	return &def
@@ -498,6 +649,8 @@ func (p *parser) parseDirectiveLocation() DirectiveLocation {
 		return LocationFragmentSpread
 	case `INLINE_FRAGMENT`:
 		return LocationInlineFragment
+	case `VARIABLE_DEFINITION`:
+		return LocationVariableDefinition
 	case `SCHEMA`:
 		return LocationSchema
 	case `SCALAR`:
@@ -4,7 +4,10 @@ import (
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
 	"github.com/dgraph-io/gqlparser/v2/parser/testrunner"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchemaDocument(t *testing.T) {
@@ -16,3 +19,132 @@ func TestSchemaDocument(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultValuePositions(t *testing.T) {
+	doc, err := ParseSchema(&ast.Source{Input: `type Query {
+  a(arg: [FilterInput!] = [{name: "x", tags: []}, {}]): Int
+}`, Name: "spec"})
+	require.Nil(t, err)
+
+	value := doc.Definitions[0].Fields[0].Arguments[0].DefaultValue
+	require.Equal(t, `[{name:"x",tags:[]},{}]`, value.String())
+
+	type position struct{ line, column int }
+	var positions []position
+	var walk func(v *ast.Value)
+	walk = func(v *ast.Value) {
+		require.NotNil(t, v.Position, v.String())
+		positions = append(positions, position{v.Position.Line, v.Position.Column})
+		for _, child := range v.Children {
+			require.NotNil(t, child.Position, child.Value.String())
+			walk(child.Value)
+		}
+	}
+	walk(value)
+
+	require.Equal(t, []position{
+		{2, 27}, // [
+		{2, 28}, // {name...
+		{2, 36}, // "x", strings start after the opening quote
+		{2, 46}, // []
+		{2, 51}, // {}
+	}, positions)
+}
+
+func TestCommentDescriptions(t *testing.T) {
+	src := &ast.Source{Input: `# Not a description, there is a blank line
+
+# The query root
+#   with indented lines
+type Query {
+  # comment only
+  a: Int
+  "description only"
+  b: Int
+  # both, the description wins
+  "description"
+  c: Int # trailing comments are ignored
+  d(
+    # argument
+    arg: Int
+  ): Int
+}
+
+# enum
+enum E {
+  # value
+  A
+}
+
+# extensions can't have descriptions
+extend type Query {
+  e: Int
+}
+`}
+
+	doc, err := ParseSchema(src, lexer.WithComments())
+	require.Nil(t, err)
+
+	query := doc.Definitions.ForName("Query")
+	require.Equal(t, "The query root\n  with indented lines", query.Description)
+	require.Equal(t, "comment only", query.Fields.ForName("a").Description)
+	require.Equal(t, "description only", query.Fields.ForName("b").Description)
+	require.Equal(t, "description", query.Fields.ForName("c").Description)
+	require.Equal(t, "", query.Fields.ForName("d").Description)
+	require.Equal(t, "argument", query.Fields.ForName("d").Arguments.ForName("arg").Description)
+	require.Equal(t, "enum", doc.Definitions.ForName("E").Description)
+	require.Equal(t, "value", doc.Definitions.ForName("E").EnumValues.ForName("A").Description)
+	require.Equal(t, "", doc.Extensions[0].Description)
+
+	t.Run("comments are ignored by default", func(t *testing.T) {
+		doc, err := ParseSchema(src)
+		require.Nil(t, err)
+
+		query := doc.Definitions.ForName("Query")
+		require.Equal(t, "", query.Description)
+		require.Equal(t, "", query.Fields.ForName("a").Description)
+		require.Equal(t, "description only", query.Fields.ForName("b").Description)
+		require.Equal(t, "description", query.Fields.ForName("c").Description)
+	})
+}
+
+func TestParseSchemas(t *testing.T) {
+	a := &ast.Source{Name: "a.graphql", Input: "type Query {\n  user: User\n}\ntype User {\n  id: ID!\n}\ndirective @tag on FIELD_DEFINITION\n"}
+
+	t.Run("duplicate type", func(t *testing.T) {
+		b := &ast.Source{Name: "b.graphql", Input: "scalar Time\n\ntype User {\n  name: String\n}\n"}
+
+		_, err := ParseSchemas(a, b)
+		require.NotNil(t, err)
+		require.Equal(t, `Type "User" defined in a.graphql and b.graphql.`, err.Message)
+		require.Equal(t, []gqlerror.Location{
+			{Line: 3, Column: 6, Source: "b.graphql", Start: 18, End: 22},
+			{Line: 4, Column: 6, Source: "a.graphql", Start: 33, End: 37},
+		}, err.Locations)
+		require.Equal(t, "b.graphql:3:6: Type \"User\" defined in a.graphql and b.graphql.", err.Error())
+	})
+
+	t.Run("duplicate directive", func(t *testing.T) {
+		b := &ast.Source{Name: "b.graphql", Input: "directive @tag on OBJECT"}
+
+		_, err := ParseSchemas(a, b)
+		require.NotNil(t, err)
+		require.Equal(t, `Directive "@tag" defined in a.graphql and b.graphql.`, err.Message)
+	})
+
+	t.Run("extensions", func(t *testing.T) {
+		b := &ast.Source{Name: "b.graphql", Input: "extend type User {\n  name: String\n}\nextend type Query @tag\n"}
+
+		doc, err := ParseSchemas(a, b)
+		require.Nil(t, err)
+		require.Len(t, doc.Definitions, 2)
+		require.Len(t, doc.Extensions, 2)
+		require.Equal(t, "b.graphql", doc.Extensions[0].Position.Src.Name)
+	})
+
+	t.Run("duplicates within a source are left to the validator", func(t *testing.T) {
+		doc, err := ParseSchemas(&ast.Source{Name: "c.graphql", Input: "scalar Time scalar Time"})
+		require.Nil(t, err)
+		require.Len(t, doc.Definitions, 2)
+	})
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
+
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+)
+
+// ParseStats are counters collected while a document is parsed, eg to track parser performance over document size.
+type ParseStats struct {
+	// Tokens is the number of tokens read, not counting comments and the final EOF.
+	Tokens int
+	// Nodes is the number of ast nodes built, including the document itself.
+	Nodes int
+	// MaxDepth is the deepest nesting of braces, brackets and parentheses.
+	MaxDepth int
+}
+
+// ParseQueryWithStats is ParseQuery, also returning the stats of the parse. When parsing fails they count what was
+// read up to the error.
+func ParseQueryWithStats(source *Source, opts ...lexer.LexerOption) (*QueryDocument, ParseStats, *gqlerror.Error) {
+	stats := &ParseStats{}
+	doc, err := parseQuery(source, stats, opts...)
+	return doc, *stats, err
+}
+
+// ParseSchemaWithStats is ParseSchema, also returning the stats of the parse. When parsing fails they count what was
+// read up to the error.
+func ParseSchemaWithStats(source *Source, opts ...lexer.LexerOption) (*SchemaDocument, ParseStats, *gqlerror.Error) {
+	stats := &ParseStats{}
+	doc, err := parseSchema(source, stats, opts...)
+	return doc, *stats, err
+}
+
+func (p *parser) countToken(tok lexer.Token) {
+	switch tok.Kind {
+	case lexer.EOF:
+		return
+	case lexer.BraceL, lexer.BracketL, lexer.ParenL:
+		p.depth++
+		if p.depth > p.stats.MaxDepth {
+			p.stats.MaxDepth = p.depth
+		}
+	case lexer.BraceR, lexer.BracketR, lexer.ParenR:
+		p.depth--
+	}
+	p.stats.Tokens++
+}
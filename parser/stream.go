@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
+)
+
+// QueryStream parses a query document that arrives one token at a time, eg from an editor as the user types.
+// Each top level operation or fragment is parsed as soon as its closing brace is pushed, without waiting for, or
+// reparsing, the rest of the document. It only parses, validator.NewQueryStream wraps it to validate each
+// definition as it completes.
+type QueryStream struct {
+	tokens []lexer.Token
+	braces int
+	parens int
+}
+
+func NewQueryStream() *QueryStream {
+	return &QueryStream{}
+}
+
+// Push adds the next token. When it completes a top level definition, that definition is returned in a document
+// of its own, or an error if the definition doesn't parse. Otherwise the document is nil. A closing brace or
+// parenthesis that wasn't opened is a syntax error, it's reported straight away and the definition it was pushed
+// into is dropped. Pushing an EOF parses whatever is left, so incomplete trailing definitions are reported.
+func (s *QueryStream) Push(tok lexer.Token) (*ast.QueryDocument, *gqlerror.Error) {
+	if tok.Kind == lexer.EOF {
+		if len(s.tokens) == 0 {
+			return nil, nil
+		}
+		return s.flush(tok)
+	}
+
+	s.tokens = append(s.tokens, tok)
+	switch tok.Kind {
+	case lexer.BraceL:
+		s.braces++
+	case lexer.BraceR:
+		s.braces--
+		if s.braces < 0 {
+			return nil, s.unexpected(tok)
+		}
+		// object values in variable defaults or directive arguments close inside parentheses
+		if s.braces == 0 && s.parens == 0 {
+			return s.flush(lexer.Token{Kind: lexer.EOF, Pos: tok.Pos})
+		}
+	case lexer.ParenL:
+		s.parens++
+	case lexer.ParenR:
+		s.parens--
+		if s.parens < 0 {
+			return nil, s.unexpected(tok)
+		}
+	}
+	return nil, nil
+}
+
+// unexpected reports tok as a syntax error and drops the definition it was pushed into.
+func (s *QueryStream) unexpected(tok lexer.Token) *gqlerror.Error {
+	s.tokens, s.braces, s.parens = nil, 0, 0
+
+	var p parser
+	p.unexpectedToken(tok)
+	return p.err
+}
+
+func (s *QueryStream) flush(eof lexer.Token) (*ast.QueryDocument, *gqlerror.Error) {
+	p := parser{tokens: append(s.tokens, eof)}
+	s.tokens, s.braces, s.parens = nil, 0, 0
+
+	doc := p.parseQueryDocument()
+	if p.err != nil {
+		return nil, p.err
+	}
+	return doc, nil
+}
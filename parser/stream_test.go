@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
+)
+
+func TestQueryStream(t *testing.T) {
+	type result struct {
+		doc *ast.QueryDocument
+		err *gqlerror.Error
+	}
+
+	push := func(input string) []result {
+		l := lexer.New(&ast.Source{Input: input, Name: "spec"})
+		s := NewQueryStream()
+		var results []result
+		for {
+			tok, err := l.ReadToken()
+			require.Nil(t, err)
+			doc, perr := s.Push(tok)
+			if doc != nil || perr != nil {
+				results = append(results, result{doc, perr})
+			}
+			if tok.Kind == lexer.EOF {
+				return results
+			}
+		}
+	}
+
+	t.Run("definitions complete independently", func(t *testing.T) {
+		results := push(`
+			query Q($filter: Filter = {name: "x"}) @live(opts: {a: 1}) { users(filter: $filter) { ...UserFields } }
+			fragment UserFields on User { id name }
+		`)
+		require.Len(t, results, 2)
+
+		require.Nil(t, results[0].err)
+		require.Len(t, results[0].doc.Operations, 1)
+		require.Equal(t, "Q", results[0].doc.Operations[0].Name)
+		require.Equal(t, 2, results[0].doc.Operations[0].Position.Line)
+		require.Empty(t, results[0].doc.Fragments)
+
+		require.Nil(t, results[1].err)
+		require.Empty(t, results[1].doc.Operations)
+		require.Len(t, results[1].doc.Fragments, 1)
+		require.Equal(t, "UserFields", results[1].doc.Fragments[0].Name)
+		require.Equal(t, 3, results[1].doc.Fragments[0].Position.Line)
+	})
+
+	t.Run("errors are reported per definition", func(t *testing.T) {
+		results := push("query A { a }\nquery B($x: ) { b }\n{ c }\nquery D { d")
+		require.Len(t, results, 4)
+		require.Nil(t, results[0].err)
+		require.NotNil(t, results[1].err)
+		require.Equal(t, 2, results[1].err.Locations[0].Line)
+		require.Nil(t, results[2].err)
+		require.Equal(t, "c", results[2].doc.Operations[0].SelectionSet[0].(*ast.Field).Name)
+		require.NotNil(t, results[3].err, "unterminated definition is reported at EOF")
+	})
+
+	t.Run("unopened braces are syntax errors", func(t *testing.T) {
+		results := push("query A { a } }\nquery B { b }\nquery C ) { c }\n{ d }")
+		require.Len(t, results, 6)
+		require.Nil(t, results[0].err)
+		require.Equal(t, "spec:1:15: Unexpected }", results[1].err.Error())
+		require.Equal(t, 15, results[1].err.Locations[0].Column)
+		require.Nil(t, results[2].err)
+		require.Equal(t, "B", results[2].doc.Operations[0].Name)
+		require.Equal(t, "spec:3:9: Unexpected )", results[3].err.Error())
+		require.Equal(t, 9, results[3].err.Locations[0].Column)
+		// the rest of the definition after the error is read as a new one
+		require.Nil(t, results[4].err)
+		require.Equal(t, "c", results[4].doc.Operations[0].SelectionSet[0].(*ast.Field).Name)
+		require.Nil(t, results[5].err)
+		require.Equal(t, "d", results[5].doc.Operations[0].SelectionSet[0].(*ast.Field).Name)
+	})
+}
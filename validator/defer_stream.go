@@ -0,0 +1,12 @@
+package validator
+
+import "github.com/dgraph-io/gqlparser/v2/ast"
+
+// DeferStream defines the experimental @defer and @stream directives used for incremental delivery. They aren't
+// part of the prelude, load it along with it to opt in, and add the DeferStreamDirectiveOnValidOperations rule
+// to validate how they're used.
+var DeferStream = &ast.Source{
+	Name:    "defer_stream.graphql",
+	Input:   "\"The @defer directive may be provided for fragment spreads and inline fragments to inform the executor to delay the execution of the current fragment to indicate deprioritization of the current fragment. A query with @defer directive will cause the request to potentially return multiple responses, where non-deferred data is delivered in the initial response and data deferred is delivered in a subsequent response.\"\ndirective @defer(if: Boolean! = true, label: String) on FRAGMENT_SPREAD | INLINE_FRAGMENT\n\n\"The @stream directive may be provided for a field of List type so that the backend can leverage technology such as asynchronous iterators to provide a partial list in the initial response, and additional list items in subsequent responses.\"\ndirective @stream(if: Boolean! = true, label: String, initialCount: Int = 0) on FIELD\n",
+	BuiltIn: true,
+}
@@ -20,16 +20,30 @@ func At(position *ast.Position) ErrorOption {
 		if position == nil {
 			return
 		}
+		var file string
+		if position.Src != nil {
+			file = position.Src.Name
+		}
 		err.Locations = append(err.Locations, gqlerror.Location{
 			Line:   position.Line,
 			Column: position.Column,
+			Source: file,
+			Start:  position.Start,
+			End:    position.End,
 		})
-		if position.Src.Name != "" {
-			err.SetFile(position.Src.Name)
+		if file != "" {
+			err.SetFile(file)
 		}
 	}
 }
 
+// AtPath sets the response path of the error, usually walker.Path() for errors about a field.
+func AtPath(path ast.Path) ErrorOption {
+	return func(err *gqlerror.Error) {
+		err.Path = path
+	}
+}
+
 func SuggestListQuoted(prefix string, typed string, suggestions []string) ErrorOption {
 	suggested := SuggestionList(typed, suggestions)
 	return func(err *gqlerror.Error) {
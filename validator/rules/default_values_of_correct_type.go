@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// DefaultValuesOfCorrectType reports each variable whose default value doesn't fit its type once, eg
+// `Variable "$x" of type "Int" has invalid default value "hello".`, for tools that want a summary per variable.
+// A default value is invalid when ValuesOfCorrectType finds anything wrong in it or any value nested in it, so the
+// two rules always agree on what is invalid.
+func DefaultValuesOfCorrectType() Rule {
+	return Rule{
+		Name: "DefaultValuesOfCorrectType",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				for _, varDef := range operation.VariableDefinitions {
+					if varDef.DefaultValue == nil || varDef.Type == nil {
+						continue
+					}
+					if valueOfCorrectType(walker.Schema, varDef.DefaultValue) {
+						continue
+					}
+
+					addError(
+						Message(`Variable "$%s" of type "%s" has invalid default value %s.`, varDef.Variable, varDef.Type.String(), varDef.DefaultValue.String()),
+						At(varDef.DefaultValue.Position),
+					)
+				}
+			})
+		},
+	}
+}
+
+// valueOfCorrectType reports whether ValuesOfCorrectType accepts value and everything nested in it. The walker has
+// already set the expected types on them by the time the operation observers run.
+func valueOfCorrectType(schema *ast.Schema, value *ast.Value) bool {
+	valid := true
+	validateValueOfCorrectType(schema, func(options ...ErrorOption) { valid = false }, value)
+	for _, child := range value.Children {
+		if !valid {
+			break
+		}
+		valid = valueOfCorrectType(schema, child.Value)
+	}
+	return valid
+}
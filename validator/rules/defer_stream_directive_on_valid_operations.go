@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"strconv"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// DeferStreamDirectiveOnValidOperations checks the @defer and @stream directives of schemas loaded with the
+// DeferStream source. It rejects them in subscriptions unless their if argument can be false, @stream on fields
+// that aren't lists, labels that aren't unique static strings and negative initial counts.
+func DeferStreamDirectiveOnValidOperations() Rule {
+	return Rule{
+		Name: "DeferStreamDirectiveOnValidOperations",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			// fragments are walked on their own and wherever they are spread, only check each directive once
+			checked := map[*ast.Directive]bool{}
+			streamFields := map[*ast.Field]bool{}
+			labels := map[string]bool{}
+
+			observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+				if directive.Name != "defer" && directive.Name != "stream" {
+					return
+				}
+
+				if op := walker.CurrentOperation; op != nil && op.Operation == ast.Subscription && !ifCanBeFalse(directive) {
+					addError(
+						Message(`%s directive not supported on subscription operations. Disable "@%s" by setting the "if" argument to "false".`, directiveTitle(directive), directive.Name),
+						At(directive.Position),
+					)
+				}
+
+				if checked[directive] {
+					return
+				}
+				checked[directive] = true
+
+				if label := directive.Arguments.ForName("label"); label != nil && label.Value != nil {
+					switch label.Value.Kind {
+					case ast.StringValue, ast.BlockValue:
+						if labels[label.Value.Raw] {
+							addError(
+								Message(`Defer/Stream directive label argument must be unique.`),
+								At(label.Position),
+							)
+						}
+						labels[label.Value.Raw] = true
+					case ast.Variable:
+						addError(
+							Message(`Directive "%s"'s label argument must be a static string.`, directive.Name),
+							At(label.Position),
+						)
+					}
+				}
+
+				if directive.Name != "stream" {
+					return
+				}
+				if count := directive.Arguments.ForName("initialCount"); count != nil && count.Value != nil && count.Value.Kind == ast.IntValue {
+					if n, err := strconv.ParseInt(count.Value.Raw, 10, 64); err == nil && n < 0 {
+						addError(
+							Message(`Stream directive initialCount argument must be a non-negative integer, found %s.`, count.Value.Raw),
+							At(count.Position),
+						)
+					}
+				}
+			})
+
+			observers.OnField(func(walker *Walker, field *ast.Field) {
+				stream := field.Directives.ForName("stream")
+				if stream == nil || streamFields[field] || field.Definition == nil || field.ObjectDefinition == nil {
+					return
+				}
+				streamFields[field] = true
+				if field.Definition.Type.Elem != nil {
+					return
+				}
+				addError(
+					Message(`Stream directive cannot be used on non-list field "%s" on type "%s".`, field.Name, field.ObjectDefinition.Name),
+					At(stream.Position),
+				)
+			})
+		},
+	}
+}
+
+// ifCanBeFalse reports whether the if argument of a @defer or @stream directive may disable it.
+func ifCanBeFalse(directive *ast.Directive) bool {
+	arg := directive.Arguments.ForName("if")
+	if arg == nil || arg.Value == nil {
+		return false
+	}
+	switch arg.Value.Kind {
+	case ast.BooleanValue:
+		return arg.Value.Raw == "false"
+	case ast.Variable:
+		return true
+	default:
+		return false
+	}
+}
+
+func directiveTitle(directive *ast.Directive) string {
+	if directive.Name == "defer" {
+		return "Defer"
+	}
+	return "Stream"
+}
@@ -26,6 +26,7 @@ func init() {
 			addError(
 				Message(message),
 				At(field.Position),
+				AtPath(walker.Path()),
 			)
 		})
 	})
@@ -27,6 +27,7 @@ func init() {
 					Message(`Unknown argument "%s" on field "%s" of type "%s".`, arg.Name, field.Name, field.ObjectDefinition.Name),
 					SuggestListQuoted("Did you mean", arg.Name, suggestions),
 					At(field.Position),
+					AtPath(walker.Path()),
 				)
 			}
 		})
@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"sort"
+
 	"github.com/dgraph-io/gqlparser/v2/ast"
 	. "github.com/dgraph-io/gqlparser/v2/validator"
 )
@@ -9,8 +11,15 @@ func init() {
 	AddRule("KnownDirectives", func(observers *Events, addError AddErrFunc) {
 		observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
 			if directive.Definition == nil {
+				var suggestions []string
+				for name := range walker.Schema.Directives {
+					suggestions = append(suggestions, name)
+				}
+				sort.Strings(suggestions)
+
 				addError(
 					Message(`Unknown directive "%s".`, directive.Name),
+					SuggestListQuoted("Did you mean", directive.Name, suggestions),
 					At(directive.Position),
 				)
 				return
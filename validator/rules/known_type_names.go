@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"sort"
+
 	"github.com/dgraph-io/gqlparser/v2/ast"
 	. "github.com/dgraph-io/gqlparser/v2/validator"
 )
@@ -17,7 +19,8 @@ func init() {
 
 				addError(
 					Message(`Unknown type "%s".`, typeName),
-					At(operation.Position),
+					SuggestListQuoted("Did you mean", typeName, typeNames(walker.Schema)),
+					At(vdef.Position),
 				)
 			}
 		})
@@ -35,6 +38,7 @@ func init() {
 
 			addError(
 				Message(`Unknown type "%s".`, typedName),
+				SuggestListQuoted("Did you mean", typedName, typeNames(walker.Schema)),
 				At(inlineFragment.Position),
 			)
 		})
@@ -46,16 +50,20 @@ func init() {
 				return
 			}
 
-			var possibleTypes []string
-			for _, t := range walker.Schema.Types {
-				possibleTypes = append(possibleTypes, t.Name)
-			}
-
 			addError(
 				Message(`Unknown type "%s".`, typeName),
-				SuggestListQuoted("Did you mean", typeName, possibleTypes),
+				SuggestListQuoted("Did you mean", typeName, typeNames(walker.Schema)),
 				At(fragment.Position),
 			)
 		})
 	})
 }
+
+func typeNames(schema *ast.Schema) []string {
+	var names []string
+	for _, t := range schema.Types {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
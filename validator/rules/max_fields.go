@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// MaxFields limits the number of fields an operation selects, counted over the selection tree
+// with every fragment spread expanded, so a fragment spread ten times counts ten times. It guards against queries
+// that repeat a field under many aliases to amplify the work of a single request. The count stops at
+// math.MaxInt32, so fragments that double the fields at every level are rejected without overflowing it, and the
+// error then says the operation selects at least that many.
+func MaxFields(limit int) Rule {
+	return Rule{
+		Name: "MaxFields",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				counter := newFieldCounter(walker.Document, limit, func(field *ast.Field) bool {
+					return true
+				})
+				if count := counter.count(operation.SelectionSet); count > limit {
+					addError(
+						Message(`Operation selects %s fields, more than the limit of %d.`, counter.describe(count), limit),
+						At(operation.Position),
+					)
+				}
+			})
+		},
+	}
+}
+
+// MaxAliases limits the number of aliased fields an operation selects, counted like MaxFields with every fragment
+// spread expanded. A field is aliased when its alias differs from its name, so `name: name` isn't counted.
+func MaxAliases(limit int) Rule {
+	return Rule{
+		Name: "MaxAliases",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				counter := newFieldCounter(walker.Document, limit, func(field *ast.Field) bool {
+					return field.Alias != field.Name
+				})
+				if count := counter.count(operation.SelectionSet); count > limit {
+					addError(
+						Message(`Operation uses %s aliases, more than the limit of %d.`, counter.describe(count), limit),
+						At(operation.Position),
+					)
+				}
+			})
+		},
+	}
+}
+
+// fieldCounter counts the fields matching a predicate in a selection set and the fragments it spreads, up to
+// math.MaxInt32, or one more than a limit above that. The count of each fragment is only worked out once, so nesting fragments that spread each
+// other many times doesn't blow up the time it takes, and cycles, which NoFragmentCycles reports, count as nothing.
+type fieldCounter struct {
+	document  *ast.QueryDocument
+	match     func(field *ast.Field) bool
+	max       int
+	fragments map[string]int
+	visiting  map[string]bool
+}
+
+func newFieldCounter(document *ast.QueryDocument, limit int, match func(field *ast.Field) bool) *fieldCounter {
+	max := math.MaxInt32
+	if limit >= max {
+		max = limit + 1
+		if max < limit {
+			max = limit
+		}
+	}
+	return &fieldCounter{
+		document:  document,
+		match:     match,
+		max:       max,
+		fragments: map[string]int{},
+		visiting:  map[string]bool{},
+	}
+}
+
+// add returns a+b, or the max if that's more. Both are at most the max, so the sum can't overflow.
+func (c *fieldCounter) add(a, b int) int {
+	if a > c.max-b {
+		return c.max
+	}
+	return a + b
+}
+
+// describe returns count for an error message, as at least the max when counting stopped there
+func (c *fieldCounter) describe(count int) string {
+	if count >= c.max {
+		return "at least " + strconv.Itoa(count)
+	}
+	return strconv.Itoa(count)
+}
+
+func (c *fieldCounter) count(set ast.SelectionSet) int {
+	count := 0
+	for _, sel := range set {
+		if count >= c.max {
+			return c.max
+		}
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if c.match(sel) {
+				count = c.add(count, 1)
+			}
+			count = c.add(count, c.count(sel.SelectionSet))
+		case *ast.InlineFragment:
+			count = c.add(count, c.count(sel.SelectionSet))
+		case *ast.FragmentSpread:
+			count = c.add(count, c.fragment(sel.Name))
+		}
+	}
+	return count
+}
+
+func (c *fieldCounter) fragment(name string) int {
+	if count, ok := c.fragments[name]; ok {
+		return count
+	}
+	if c.visiting[name] {
+		return 0
+	}
+	def := c.document.Fragments.ForName(name)
+	if def == nil {
+		return 0
+	}
+
+	c.visiting[name] = true
+	count := c.count(def.SelectionSet)
+	c.visiting[name] = false
+	c.fragments[name] = count
+	return count
+}
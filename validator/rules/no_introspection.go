@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// NoIntrospection rejects the __schema and __type introspection fields, eg to hide the schema in production.
+// __typename is only rejected if allowTypename is false, as clients often rely on it to tell apart the members of
+// unions and interfaces.
+func NoIntrospection(allowTypename bool) Rule {
+	return Rule{
+		Name: "NoIntrospection",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnField(func(walker *Walker, field *ast.Field) {
+				// a fragment no operation spreads is never executed, so it can't introspect anything
+				if walker.CurrentOperation == nil {
+					return
+				}
+				switch field.Name {
+				case "__schema", "__type":
+				case "__typename":
+					if allowTypename {
+						return
+					}
+				default:
+					return
+				}
+
+				addError(
+					Message(`GraphQL introspection is not allowed.`),
+					At(field.Position),
+				)
+			})
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// NoSideEffectsInQueries rejects fields whose definition carries the given directive, eg `@mutation`, when they are
+// selected by a query operation, directly or through fragments. Mutations can still select them.
+func NoSideEffectsInQueries(directive string) Rule {
+	return Rule{
+		Name: "NoSideEffectsInQueries",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnField(func(walker *Walker, field *ast.Field) {
+				// a fragment can be spread into queries and mutations alike, it's checked for each operation using it
+				if walker.CurrentOperation == nil || field.Definition == nil || field.ObjectDefinition == nil {
+					return
+				}
+				if op := walker.CurrentOperation.Operation; op != ast.Query && op != "" {
+					return
+				}
+				if field.Definition.Directives.ForName(directive) == nil {
+					return
+				}
+
+				addError(
+					Message(`Field "%s.%s" is marked @%s and cannot be selected in a query operation.`, field.ObjectDefinition.Name, field.Name, directive),
+					At(field.Position),
+				)
+			})
+		},
+	}
+}
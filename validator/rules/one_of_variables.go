@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"github.com/dgraph-io/gqlparser/v2/ast"
+	. "github.com/dgraph-io/gqlparser/v2/validator"
+)
+
+// OneOfVariables checks a directive on operations listing variables of which exactly one should be set, eg `query Search($id: ID, $name: String) @oneOfVariables(variables: ["id", "name"])`, the operation level
+// counterpart of @oneOf input objects. The schema has to define the directive with a `variables: [String!]!`
+// argument. Variable values aren't known during validation, so the rule checks what it can: every listed name is a
+// variable of the operation, the variables are nullable, and at most one of them has a non-null default value.
+func OneOfVariables(directive string) Rule {
+	return Rule{
+		Name: "OneOfVariables",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				dir := operation.Directives.ForName(directive)
+				if dir == nil {
+					return
+				}
+				arg := dir.Arguments.ForName("variables")
+				if arg == nil || arg.Value == nil {
+					return
+				}
+
+				values := []*ast.Value{arg.Value}
+				if arg.Value.Kind == ast.ListValue {
+					values = values[:0]
+					for _, child := range arg.Value.Children {
+						values = append(values, child.Value)
+					}
+				}
+
+				var defaulted []*ast.VariableDefinition
+				for _, value := range values {
+					if value.Kind != ast.StringValue {
+						continue
+					}
+					varDef := operation.VariableDefinitions.ForName(value.Raw)
+					if varDef == nil {
+						if operation.Name != "" {
+							addError(
+								Message(`Unknown variable "$%s" in @%s on operation "%s".`, value.Raw, directive, operation.Name),
+								At(value.Position),
+							)
+						} else {
+							addError(
+								Message(`Unknown variable "$%s" in @%s.`, value.Raw, directive),
+								At(value.Position),
+							)
+						}
+						continue
+					}
+					if varDef.Type.NonNull {
+						addError(
+							Message(`Variable "$%s" must be nullable to be used in @%s.`, varDef.Variable, directive),
+							At(varDef.Position),
+						)
+					}
+					if varDef.DefaultValue != nil && varDef.DefaultValue.Kind != ast.NullValue {
+						defaulted = append(defaulted, varDef)
+					}
+				}
+
+				if len(defaulted) > 1 {
+					addError(
+						Message(`Only one of the variables in @%s can have a non-null default value, found defaults for "$%s" and "$%s".`, directive, defaulted[0].Variable, defaulted[1].Variable),
+						At(defaulted[1].Position),
+					)
+				}
+			})
+		},
+	}
+}
@@ -30,6 +30,7 @@ func init() {
 				addError(
 					Message(`Field "%s" argument "%s" of type "%s" is required but not provided.`, field.Name, argDef.Name, argDef.Type.String()),
 					At(field.Position),
+					AtPath(walker.Path()),
 				)
 			}
 		})
@@ -12,7 +12,7 @@ func init() {
 				return
 			}
 
-			fieldType := walker.Schema.Types[field.Definition.Type.Name()]
+			fieldType := walker.Schema.TypeFromAST(field.Definition.Type)
 			if fieldType == nil {
 				return
 			}
@@ -21,6 +21,7 @@ func init() {
 				addError(
 					Message(`Field "%s" must not have a selection since type "%s" has no subfields.`, field.Name, fieldType.Name),
 					At(field.Position),
+					AtPath(walker.Path()),
 				)
 			}
 
@@ -29,6 +30,7 @@ func init() {
 					Message(`Field "%s" of type "%s" must have a selection of subfields.`, field.Name, field.Definition.Type.String()),
 					Suggestf(`"%s { ... }"`, field.Name),
 					At(field.Position),
+					AtPath(walker.Path()),
 				)
 			}
 		})
@@ -14,17 +14,58 @@ func init() {
 				return
 			}
 
-			if len(operation.SelectionSet) > 1 {
-				name := "Anonymous Subscription"
-				if operation.Name != "" {
-					name = `Subscription ` + strconv.Quote(operation.Name)
-				}
+			name := "Anonymous Subscription"
+			if operation.Name != "" {
+				name = `Subscription ` + strconv.Quote(operation.Name)
+			}
 
+			if len(operation.SelectionSet) > 1 {
 				addError(
 					Message(`%s must select only one top level field.`, name),
 					At(operation.SelectionSet[1].GetPosition()),
 				)
 			}
+
+			// the root field must always be resolved, so it can't be skipped directly or through the
+			// fragments it is selected in
+			if directive := conditionalRootSelection(walker.Document, operation.SelectionSet, map[string]bool{}); directive != nil {
+				addError(
+					Message("%s must not use `@skip` or `@include` directives in the top level selection.", name),
+					At(directive.Position),
+				)
+			}
 		})
 	})
 }
+
+// conditionalRootSelection returns the first @skip or @include directive that applies to a field of the
+// selection set, following inline fragments and fragment spreads.
+func conditionalRootSelection(doc *ast.QueryDocument, selectionSet ast.SelectionSet, visited map[string]bool) *ast.Directive {
+	for _, selection := range selectionSet {
+		var directives ast.DirectiveList
+		var children ast.SelectionSet
+		switch selection := selection.(type) {
+		case *ast.Field:
+			directives = selection.Directives
+		case *ast.InlineFragment:
+			directives = selection.Directives
+			children = selection.SelectionSet
+		case *ast.FragmentSpread:
+			directives = selection.Directives
+			if fragment := doc.Fragments.ForName(selection.Name); fragment != nil && !visited[selection.Name] {
+				visited[selection.Name] = true
+				children = fragment.SelectionSet
+			}
+		}
+
+		for _, directive := range directives {
+			if directive.Name == "skip" || directive.Name == "include" {
+				return directive
+			}
+		}
+		if directive := conditionalRootSelection(doc, children, visited); directive != nil {
+			return directive
+		}
+	}
+	return nil
+}
@@ -11,6 +11,9 @@ func init() {
 			seen := map[string]bool{}
 
 			for _, dir := range directives {
+				if dir.Definition != nil && dir.Definition.IsRepeatable {
+					continue
+				}
 				if seen[dir.Name] {
 					addError(
 						Message(`The directive "%s" can only be used once at this location.`, dir.Name),
@@ -10,116 +10,153 @@ import (
 func init() {
 	AddRule("ValuesOfCorrectType", func(observers *Events, addError AddErrFunc) {
 		observers.OnValue(func(walker *Walker, value *ast.Value) {
-			if value.Definition == nil || value.ExpectedType == nil {
-				return
-			}
-
-			if value.Definition.Kind == ast.Scalar {
-				// Skip custom validating scalars
-				if !value.Definition.OneOf("Int", "Float", "String", "Boolean", "ID") {
-					return
-				}
-			}
-
-			var possibleEnums []string
-			if value.Definition.Kind == ast.Enum {
-				for _, val := range value.Definition.EnumValues {
-					possibleEnums = append(possibleEnums, val.Name)
-				}
-			}
-
-			rawVal, err := value.Value(nil)
-			if err != nil {
-				unexpectedTypeMessage(addError, value)
-			}
-
-			switch value.Kind {
-			case ast.NullValue:
-				if value.ExpectedType.NonNull {
-					unexpectedTypeMessage(addError, value)
-				}
-
-			case ast.ListValue:
-				if value.ExpectedType.Elem == nil {
-					unexpectedTypeMessage(addError, value)
-					return
-				}
-
-			case ast.IntValue:
-				if !value.Definition.OneOf("Int", "Float", "ID") {
-					unexpectedTypeMessage(addError, value)
-				}
-
-			case ast.FloatValue:
-				if !value.Definition.OneOf("Float") {
-					unexpectedTypeMessage(addError, value)
-				}
-
-			case ast.StringValue, ast.BlockValue:
-				if value.Definition.Kind == ast.Enum {
-					rawValStr := fmt.Sprint(rawVal)
-					addError(
-						Message("Expected type %s, found %s.", value.ExpectedType.String(), value.String()),
-						SuggestListUnquoted("Did you mean the enum value", rawValStr, possibleEnums),
-						At(value.Position),
-					)
-				} else if !value.Definition.OneOf("String", "ID") {
-					unexpectedTypeMessage(addError, value)
-				}
+			validateValueOfCorrectType(walker.Schema, addError, value)
+		})
+	})
+}
 
-			case ast.EnumValue:
-				if value.Definition.Kind != ast.Enum || value.Definition.EnumValues.ForName(value.Raw) == nil {
-					rawValStr := fmt.Sprint(rawVal)
+// validateValueOfCorrectType reports any way value doesn't fit the type the walker expects for it. Only the value
+// itself is checked, its children are checked as they are walked.
+func validateValueOfCorrectType(schema *ast.Schema, addError AddErrFunc, value *ast.Value) {
+	if value.Definition == nil || value.ExpectedType == nil {
+		return
+	}
+
+	if value.Definition.Kind == ast.Scalar {
+		// Skip custom validating scalars
+		if !value.Definition.OneOf("Int", "Float", "String", "Boolean", "ID") {
+			return
+		}
+	}
+
+	var possibleEnums []string
+	if value.Definition.Kind == ast.Enum {
+		for _, val := range value.Definition.EnumValues {
+			possibleEnums = append(possibleEnums, val.Name)
+		}
+	}
+
+	rawVal, err := value.Value(nil)
+	if err != nil {
+		unexpectedTypeMessage(addError, value)
+	}
+
+	switch value.Kind {
+	case ast.NullValue:
+		if value.ExpectedType.NonNull {
+			unexpectedTypeMessage(addError, value)
+		}
+
+	case ast.ListValue:
+		if value.ExpectedType.Elem == nil {
+			unexpectedTypeMessage(addError, value)
+			return
+		}
+
+	case ast.IntValue:
+		if !value.Definition.OneOf("Int", "Float", "ID") {
+			unexpectedTypeMessage(addError, value)
+		}
+
+	case ast.FloatValue:
+		if !value.Definition.OneOf("Float") {
+			unexpectedTypeMessage(addError, value)
+		}
+
+	case ast.StringValue, ast.BlockValue:
+		if value.Definition.Kind == ast.Enum {
+			rawValStr := fmt.Sprint(rawVal)
+			addError(
+				Message("Expected type %s, found %s.", value.ExpectedType.String(), value.String()),
+				SuggestListUnquoted("Did you mean the enum value", rawValStr, possibleEnums),
+				At(value.Position),
+			)
+		} else if !value.Definition.OneOf("String", "ID") {
+			unexpectedTypeMessage(addError, value)
+		}
+
+	case ast.EnumValue:
+		if value.Definition.Kind != ast.Enum || value.Definition.EnumValues.ForName(value.Raw) == nil {
+			rawValStr := fmt.Sprint(rawVal)
+			addError(
+				Message("Expected type %s, found %s.", value.ExpectedType.String(), value.String()),
+				SuggestListUnquoted("Did you mean the enum value", rawValStr, possibleEnums),
+				At(value.Position),
+			)
+		}
+
+	case ast.BooleanValue:
+		if !value.Definition.OneOf("Boolean") {
+			unexpectedTypeMessage(addError, value)
+		}
+
+	case ast.ObjectValue:
+		if schema.IsOneOf(value.Definition) {
+			validateOneOf(addError, value)
+		}
+
+		for _, field := range value.Definition.Fields {
+			if field.Type.NonNull {
+				fieldValue := value.Children.ForName(field.Name)
+				if fieldValue == nil && field.DefaultValue == nil {
 					addError(
-						Message("Expected type %s, found %s.", value.ExpectedType.String(), value.String()),
-						SuggestListUnquoted("Did you mean the enum value", rawValStr, possibleEnums),
+						Message("Field %s.%s of required type %s was not provided.", value.Definition.Name, field.Name, field.Type.String()),
 						At(value.Position),
 					)
+					continue
 				}
+			}
+		}
 
-			case ast.BooleanValue:
-				if !value.Definition.OneOf("Boolean") {
-					unexpectedTypeMessage(addError, value)
+		for _, fieldValue := range value.Children {
+			if value.Definition.Fields.ForName(fieldValue.Name) == nil {
+				var suggestions []string
+				for _, fieldValue := range value.Definition.Fields {
+					suggestions = append(suggestions, fieldValue.Name)
 				}
 
-			case ast.ObjectValue:
-
-				for _, field := range value.Definition.Fields {
-					if field.Type.NonNull {
-						fieldValue := value.Children.ForName(field.Name)
-						if fieldValue == nil && field.DefaultValue == nil {
-							addError(
-								Message("Field %s.%s of required type %s was not provided.", value.Definition.Name, field.Name, field.Type.String()),
-								At(value.Position),
-							)
-							continue
-						}
-					}
-				}
+				addError(
+					Message(`Field "%s" is not defined by type %s.`, fieldValue.Name, value.Definition.Name),
+					SuggestListUnquoted("Did you mean", fieldValue.Name, suggestions),
+					At(fieldValue.Position),
+				)
+			}
+		}
 
-				for _, fieldValue := range value.Children {
-					if value.Definition.Fields.ForName(fieldValue.Name) == nil {
-						var suggestions []string
-						for _, fieldValue := range value.Definition.Fields {
-							suggestions = append(suggestions, fieldValue.Name)
-						}
-
-						addError(
-							Message(`Field "%s" is not defined by type %s.`, fieldValue.Name, value.Definition.Name),
-							SuggestListUnquoted("Did you mean", fieldValue.Name, suggestions),
-							At(fieldValue.Position),
-						)
-					}
-				}
+	case ast.Variable:
+		return
 
-			case ast.Variable:
-				return
+	default:
+		panic(fmt.Errorf("unhandled %T", value))
+	}
+}
 
-			default:
-				panic(fmt.Errorf("unhandled %T", value))
-			}
-		})
-	})
+// validateOneOf checks that exactly one non-null field is given for a @oneOf input object. Lists of
+// @oneOf objects are covered too, as the walker visits each list element with its element type.
+func validateOneOf(addError AddErrFunc, value *ast.Value) {
+	if len(value.Children) != 1 {
+		addError(
+			Message(`OneOf Input Object "%s" must specify exactly one key.`, value.Definition.Name),
+			At(value.Position),
+		)
+		return
+	}
+
+	fieldValue := value.Children[0]
+	switch fieldValue.Value.Kind {
+	case ast.NullValue:
+		addError(
+			Message(`Field "%s.%s" must be non-null.`, value.Definition.Name, fieldValue.Name),
+			At(fieldValue.Position),
+		)
+	case ast.Variable:
+		if varDef := fieldValue.Value.VariableDefinition; varDef != nil && !varDef.Type.NonNull {
+			addError(
+				Message(`Variable "$%s" must be non-nullable to be used for OneOf Input Object "%s".`, varDef.Variable, value.Definition.Name),
+				At(fieldValue.Position),
+			)
+		}
+	}
 }
 
 func unexpectedTypeMessage(addError AddErrFunc, v *ast.Value) {
@@ -12,15 +12,16 @@ func init() {
 				return
 			}
 
-			// todo: move me into walk
-			// If there is a default non nullable types can be null
-			if value.VariableDefinition.DefaultValue != nil && value.VariableDefinition.DefaultValue.Kind != ast.NullValue {
-				if value.ExpectedType.NonNull {
-					value.ExpectedType.NonNull = false
-				}
+			// If there is a non null default, nullable variables can be used in non null positions.
+			// ExpectedType belongs to the schema, so it must be copied rather than modified.
+			expectedType := value.ExpectedType
+			if expectedType.NonNull && value.VariableDefinition.DefaultValue != nil && value.VariableDefinition.DefaultValue.Kind != ast.NullValue {
+				nullable := *expectedType
+				nullable.NonNull = false
+				expectedType = &nullable
 			}
 
-			if !value.VariableDefinition.Type.IsCompatible(value.ExpectedType) {
+			if !value.VariableDefinition.Type.IsCompatible(expectedType) {
 				addError(
 					Message(
 						`Variable "%s" of type "%s" used in position expecting type "%s".`,
@@ -8,18 +8,90 @@ import (
 
 	. "github.com/dgraph-io/gqlparser/v2/ast"
 	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
 	"github.com/dgraph-io/gqlparser/v2/parser"
 )
 
+// SchemaOption configures how a schema is loaded and the additional checks made while validating it.
+type SchemaOption func(cfg *schemaConfig)
+
+type schemaConfig struct {
+	allowedDirectives   map[string]bool
+	commentDescriptions bool
+	encoding            lexer.Encoding
+	strictExtensions    bool
+}
+
+// WithCommentDescriptions uses the # comments right above a definition, field, argument or enum value as its
+// description when it doesn't have one, for legacy schemas written before descriptions were strings. It only
+// applies when parsing sources with LoadSchemaWithOptions.
+func WithCommentDescriptions() SchemaOption {
+	return func(cfg *schemaConfig) {
+		cfg.commentDescriptions = true
+	}
+}
+
+// WithEncoding reads sources in enc, eg lexer.Latin1, rather than UTF-8. Built in sources such as the Prelude
+// are always UTF-8. Like WithCommentDescriptions it only applies when parsing sources with LoadSchemaWithOptions.
+func WithEncoding(enc lexer.Encoding) SchemaOption {
+	return func(cfg *schemaConfig) {
+		cfg.encoding = enc
+	}
+}
+
+// WithAllowedDirectives rejects any directive applied in the schema whose name isn't in names,
+// even if the directive is defined. Built in directives are always allowed.
+func WithAllowedDirectives(names []string) SchemaOption {
+	return func(cfg *schemaConfig) {
+		cfg.allowedDirectives = map[string]bool{}
+		for _, name := range names {
+			cfg.allowedDirectives[name] = true
+		}
+	}
+}
+
+// WithStrictExtensions reports an extension of a type that none of the sources define. Without it such an
+// extension defines the type, which federated schemas rely on to extend types owned by another service. Extensions
+// are merged once every source is loaded, so the base type can be in a later source either way.
+func WithStrictExtensions() SchemaOption {
+	return func(cfg *schemaConfig) {
+		cfg.strictExtensions = true
+	}
+}
+
+// LoadSchema parses the sources, as parser.ParseSchemas does, and validates them into a schema. A type or directive
+// defined in more than one source is reported at both definitions.
 func LoadSchema(inputs ...*Source) (*Schema, *gqlerror.Error) {
-	ast, err := parser.ParseSchemas(inputs...)
+	return LoadSchemaWithOptions(inputs)
+}
+
+// LoadSchemaWithOptions is LoadSchema with additional schema validation options.
+func LoadSchemaWithOptions(inputs []*Source, opts ...SchemaOption) (*Schema, *gqlerror.Error) {
+	var cfg schemaConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	var lexerOpts []lexer.LexerOption
+	if cfg.commentDescriptions {
+		lexerOpts = append(lexerOpts, lexer.WithComments())
+	}
+	if cfg.encoding != nil {
+		lexerOpts = append(lexerOpts, lexer.WithEncoding(cfg.encoding))
+	}
+
+	ast, err := parser.ParseSchemasWithOptions(inputs, lexerOpts...)
 	if err != nil {
 		return nil, err
 	}
-	return ValidateSchemaDocument(ast)
+	return ValidateSchemaDocument(ast, opts...)
 }
 
-func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
+func ValidateSchemaDocument(ast *SchemaDocument, opts ...SchemaOption) (*Schema, *gqlerror.Error) {
+	var cfg schemaConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
 	schema := Schema{
 		Types:         map[string]*Definition{},
 		Directives:    map[string]*DirectiveDefinition{},
@@ -38,6 +110,9 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 
 	for _, ext := range ast.Extensions {
 		def := schema.Types[ext.Name]
+		if def == nil && cfg.strictExtensions {
+			return nil, gqlerror.ErrorPosf(ext.Position, "Cannot extend type %s because it is not defined.", ext.Name)
+		}
 		if def == nil {
 			schema.Types[ext.Name] = &Definition{
 				Kind:     ext.Kind,
@@ -60,19 +135,7 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 	}
 
 	for _, def := range defs {
-		switch def.Kind {
-		case Union:
-			for _, t := range def.Types {
-				schema.AddPossibleType(def.Name, schema.Types[t])
-				schema.AddImplements(t, def)
-			}
-		case InputObject, Object:
-			for _, intf := range def.Interfaces {
-				schema.AddPossibleType(intf, def)
-				schema.AddImplements(def.Name, schema.Types[intf])
-			}
-			schema.AddPossibleType(def.Name, def)
-		}
+		indexDefinition(&schema, def)
 	}
 
 	for i, dir := range ast.Directives {
@@ -87,6 +150,7 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 	}
 
 	if len(ast.Schema) == 1 {
+		schema.Description = ast.Schema[0].Description
 		for _, entrypoint := range ast.Schema[0].OperationTypes {
 			def := schema.Types[entrypoint.Type]
 			if def == nil {
@@ -120,6 +184,16 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 		}
 	}
 
+	// the schema definition and its extensions share one location, so a directive can't be repeated across them
+	var schemaDirectives DirectiveList
+	for _, def := range append(append(SchemaDefinitionList{}, ast.Schema...), ast.SchemaExtension...) {
+		schemaDirectives = append(schemaDirectives, def.Directives...)
+	}
+	if err := validateDirectives(&schema, schemaDirectives, LocationSchema, nil); err != nil {
+		return nil, err
+	}
+	schema.SchemaDirectives = schemaDirectives
+
 	for _, typ := range schema.Types {
 		err := validateDefinition(&schema, typ)
 		if err != nil {
@@ -134,16 +208,25 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 		}
 	}
 
-	if schema.Query == nil && schema.Types["Query"] != nil {
-		schema.Query = schema.Types["Query"]
+	if cfg.allowedDirectives != nil {
+		if err := validateAllowedDirectives(&schema, ast, cfg.allowedDirectives); err != nil {
+			return nil, err
+		}
 	}
 
-	if schema.Mutation == nil && schema.Types["Mutation"] != nil {
-		schema.Mutation = schema.Types["Mutation"]
-	}
+	// the default root names only apply without a schema definition, which otherwise lists every root there is
+	if len(ast.Schema) == 0 {
+		if schema.Query == nil && schema.Types["Query"] != nil {
+			schema.Query = schema.Types["Query"]
+		}
+
+		if schema.Mutation == nil && schema.Types["Mutation"] != nil {
+			schema.Mutation = schema.Types["Mutation"]
+		}
 
-	if schema.Subscription == nil && schema.Types["Subscription"] != nil {
-		schema.Subscription = schema.Types["Subscription"]
+		if schema.Subscription == nil && schema.Types["Subscription"] != nil {
+			schema.Subscription = schema.Types["Subscription"]
+		}
 	}
 
 	if schema.Query != nil {
@@ -166,6 +249,63 @@ func ValidateSchemaDocument(ast *SchemaDocument) (*Schema, *gqlerror.Error) {
 	return &schema, nil
 }
 
+// indexDefinition adds def to the PossibleTypes and Implements indexes of the schema.
+func indexDefinition(schema *Schema, def *Definition) {
+	switch def.Kind {
+	case Union:
+		for _, t := range def.Types {
+			schema.AddPossibleType(def.Name, schema.Types[t])
+			schema.AddImplements(t, def)
+		}
+	case InputObject, Object:
+		for _, intf := range def.Interfaces {
+			schema.AddPossibleType(intf, def)
+			schema.AddImplements(def.Name, schema.Types[intf])
+		}
+		schema.AddPossibleType(def.Name, def)
+	}
+}
+
+// AddDefinition adds a type to a loaded schema, such as one generated at runtime, without reloading it. The type
+// is validated as LoadSchema would, and may refer to itself and to anything already in the schema. If it is
+// invalid or the schema already has a type of the same name the schema is left unchanged. Adding a type never
+// changes the root operation types. It panics if the schema is frozen.
+func AddDefinition(schema *Schema, def *Definition) *gqlerror.Error {
+	if schema.Frozen() {
+		panic("validator: cannot add a definition to a frozen schema")
+	}
+	if schema.Types[def.Name] != nil {
+		return gqlerror.ErrorPosf(def.Position, "Cannot redeclare type %s.", def.Name)
+	}
+
+	schema.Types[def.Name] = def
+	if err := validateDefinition(schema, def); err != nil {
+		delete(schema.Types, def.Name)
+		return err
+	}
+	indexDefinition(schema, def)
+	return nil
+}
+
+// AddDirective adds a directive definition to a loaded schema without reloading it. It is validated as LoadSchema
+// would, and if it is invalid or the schema already has a directive of the same name the schema is left unchanged.
+// It panics if the schema is frozen.
+func AddDirective(schema *Schema, def *DirectiveDefinition) *gqlerror.Error {
+	if schema.Frozen() {
+		panic("validator: cannot add a directive to a frozen schema")
+	}
+	if schema.Directives[def.Name] != nil {
+		return gqlerror.ErrorPosf(def.Position, "Cannot redeclare directive %s.", def.Name)
+	}
+
+	schema.Directives[def.Name] = def
+	if err := validateDirective(schema, def); err != nil {
+		delete(schema.Directives, def.Name)
+		return err
+	}
+	return nil
+}
+
 func validateDirective(schema *Schema, def *DirectiveDefinition) *gqlerror.Error {
 	if err := validateName(def.Position, def.Name); err != nil {
 		// now, GraphQL spec doesn't have reserved directive name
@@ -176,6 +316,11 @@ func validateDirective(schema *Schema, def *DirectiveDefinition) *gqlerror.Error
 }
 
 func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
+	fieldLocation := LocationFieldDefinition
+	if def.Kind == InputObject {
+		fieldLocation = LocationInputFieldDefinition
+	}
+
 	for _, field := range def.Fields {
 		if err := validateName(field.Position, field.Name); err != nil {
 			// now, GraphQL spec doesn't have reserved field name
@@ -187,7 +332,25 @@ func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
 		if err := validateArgs(schema, field.Arguments, nil); err != nil {
 			return err
 		}
-		if err := validateDirectives(schema, field.Directives, LocationFieldDefinition, nil); err != nil {
+		if err := validateDirectives(schema, field.Directives, fieldLocation, nil); err != nil {
+			return err
+		}
+		if def.Kind == InputObject && field.Type.NonNull && field.DefaultValue == nil {
+			if deprecated, _ := field.IsDeprecated(); deprecated {
+				return gqlerror.ErrorPosf(field.Position, "Required input field %s.%s cannot be deprecated.", def.Name, field.Name)
+			}
+		}
+	}
+
+	for _, value := range def.EnumValues {
+		if err := validateName(value.Position, value.Name); err != nil {
+			return err
+		}
+		// the parser rejects these too, this catches definitions built in code
+		if value.Name == "true" || value.Name == "false" || value.Name == "null" {
+			return gqlerror.ErrorPosf(value.Position, `Name "%s" is reserved and cannot be used for an enum value.`, value.Name)
+		}
+		if err := validateDirectives(schema, value.Directives, LocationEnumValue, nil); err != nil {
 			return err
 		}
 	}
@@ -202,7 +365,13 @@ func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
 		}
 	}
 
+	implemented := map[string]bool{}
 	for _, intf := range def.Interfaces {
+		// an extension may list an interface the type already implements
+		if implemented[intf] {
+			return gqlerror.ErrorPosf(def.Position, "Type %s can only implement %s once.", def.Name, intf)
+		}
+		implemented[intf] = true
 		if err := validateImplements(schema, def, intf); err != nil {
 			return err
 		}
@@ -211,7 +380,7 @@ func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
 	switch def.Kind {
 	case Object, Interface:
 		if len(def.Fields) == 0 {
-			return gqlerror.ErrorPosf(def.Position, "%s must define one or more fields.", def.Kind)
+			return gqlerror.ErrorPosf(def.Position, "Type %s must define one or more fields.", strconv.Quote(def.Name))
 		}
 		for _, field := range def.Fields {
 			if typ, ok := schema.Types[field.Type.Name()]; ok {
@@ -226,7 +395,7 @@ func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
 		}
 	case InputObject:
 		if len(def.Fields) == 0 {
-			return gqlerror.ErrorPosf(def.Position, "%s must define one or more input fields.", def.Kind)
+			return gqlerror.ErrorPosf(def.Position, "Type %s must define one or more input fields.", strconv.Quote(def.Name))
 		}
 		for _, field := range def.Fields {
 			if typ, ok := schema.Types[field.Type.Name()]; ok {
@@ -234,6 +403,14 @@ func validateDefinition(schema *Schema, def *Definition) *gqlerror.Error {
 					return gqlerror.ErrorPosf(field.Position, "%s field must be one of %s.", def.Kind, kindList(Scalar, Enum, InputObject))
 				}
 			}
+			if schema.IsOneOf(def) {
+				if field.Type.NonNull {
+					return gqlerror.ErrorPosf(field.Position, "OneOf input field %s.%s must be nullable.", def.Name, field.Name)
+				}
+				if field.DefaultValue != nil {
+					return gqlerror.ErrorPosf(field.Position, "OneOf input field %s.%s cannot have a default value.", def.Name, field.Name)
+				}
+			}
 		}
 	}
 
@@ -272,7 +449,7 @@ func validateArgs(schema *Schema, args ArgumentDefinitionList, currentDirective
 		if err := validateTypeRef(schema, arg.Type); err != nil {
 			return err
 		}
-		def := schema.Types[arg.Type.Name()]
+		def := schema.TypeFromAST(arg.Type)
 		if !def.IsInputType() {
 			return gqlerror.ErrorPosf(
 				arg.Position,
@@ -285,6 +462,9 @@ func validateArgs(schema *Schema, args ArgumentDefinitionList, currentDirective
 		if err := validateDirectives(schema, arg.Directives, LocationArgumentDefinition, currentDirective); err != nil {
 			return err
 		}
+		if deprecated, _ := arg.IsDeprecated(); deprecated && arg.Type.NonNull && arg.DefaultValue == nil {
+			return gqlerror.ErrorPosf(arg.Position, "Required argument %s cannot be deprecated.", arg.Name)
+		}
 	}
 	return nil
 }
@@ -295,16 +475,22 @@ func validateDirectiveArgs(dir *Directive, schema *Schema) *gqlerror.Error {
 		allowedArgs[arg.Name] = struct{}{}
 	}
 
+	given := make(map[string]bool, len(dir.Arguments))
 	for _, arg := range dir.Arguments {
 		if _, ok := allowedArgs[arg.Name]; !ok {
 			return gqlerror.ErrorPosf(dir.Position, "%s is not supported as an argument for %s directive.", arg.Name, dir.Name)
 		}
+		if given[arg.Name] {
+			return gqlerror.ErrorPosf(arg.Position, "There can be only one argument named %s.", strconv.Quote(arg.Name))
+		}
+		given[arg.Name] = true
 	}
 	return nil
 
 }
 
 func validateDirectives(schema *Schema, dirs DirectiveList, location DirectiveLocation, currentDirective *DirectiveDefinition) *gqlerror.Error {
+	seen := map[string]bool{}
 	for _, dir := range dirs {
 		if err := validateName(dir.Position, dir.Name); err != nil {
 			// now, GraphQL spec doesn't have reserved directive name
@@ -328,11 +514,70 @@ func validateDirectives(schema *Schema, dirs DirectiveList, location DirectiveLo
 		if !validKind {
 			return gqlerror.ErrorPosf(dir.Position, "Directive %s is not applicable on %s.", dir.Name, location)
 		}
+		if seen[dir.Name] && !schema.Directives[dir.Name].IsRepeatable {
+			return gqlerror.ErrorPosf(dir.Position, `The directive "%s" can only be used once at this location.`, dir.Name)
+		}
+		seen[dir.Name] = true
 		dir.Definition = schema.Directives[dir.Name]
 	}
 	return nil
 }
 
+func validateAllowedDirectives(schema *Schema, ast *SchemaDocument, allowed map[string]bool) *gqlerror.Error {
+	check := func(dirs DirectiveList) *gqlerror.Error {
+		for _, dir := range dirs {
+			if allowed[dir.Name] {
+				continue
+			}
+			if def := schema.Directives[dir.Name]; def != nil && def.BuiltIn {
+				continue
+			}
+			return gqlerror.ErrorPosf(dir.Position, `Directive "@%s" is not permitted.`, dir.Name)
+		}
+		return nil
+	}
+	checkArgs := func(args ArgumentDefinitionList) *gqlerror.Error {
+		for _, arg := range args {
+			if err := check(arg.Directives); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, def := range append(append(SchemaDefinitionList{}, ast.Schema...), ast.SchemaExtension...) {
+		if err := check(def.Directives); err != nil {
+			return err
+		}
+	}
+
+	for _, def := range append(append(DefinitionList{}, ast.Definitions...), ast.Extensions...) {
+		if err := check(def.Directives); err != nil {
+			return err
+		}
+		for _, field := range def.Fields {
+			if err := check(field.Directives); err != nil {
+				return err
+			}
+			if err := checkArgs(field.Arguments); err != nil {
+				return err
+			}
+		}
+		for _, value := range def.EnumValues {
+			if err := check(value.Directives); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, dir := range ast.Directives {
+		if err := checkArgs(dir.Arguments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func validateImplements(schema *Schema, def *Definition, intfName string) *gqlerror.Error {
 	// see validation rules at the bottom of
 	// https://facebook.github.io/graphql/June2018/#sec-Objects
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
 	"github.com/dgraph-io/gqlparser/v2/parser/testrunner"
 	"github.com/stretchr/testify/require"
 )
@@ -56,6 +58,95 @@ func TestLoadSchema(t *testing.T) {
 		require.Equal(t, "owner", s.Types["Dog"].Fields[1].Name)
 	})
 
+	t.Run("extensions loaded before their base type", func(t *testing.T) {
+		s, err := LoadSchema(Prelude, &ast.Source{Name: "extensions.graphql", Input: `
+			extend type User {
+				email: String
+			}
+			extend enum Role { ADMIN }
+		`}, &ast.Source{Name: "schema.graphql", Input: `
+			type Query { user: User }
+			"A user."
+			type User { name: String }
+			enum Role { GUEST }
+		`})
+		require.Nil(t, err)
+
+		user := s.Types["User"]
+		require.Equal(t, "A user.", user.Description)
+		require.Equal(t, "schema.graphql", user.Position.Src.Name)
+		require.Equal(t, "name", user.Fields[0].Name)
+		require.Equal(t, "email", user.Fields[1].Name)
+		require.Equal(t, "GUEST", s.Types["Role"].EnumValues[0].Name)
+		require.Equal(t, "ADMIN", s.Types["Role"].EnumValues[1].Name)
+
+		_, err = LoadSchema(Prelude, &ast.Source{Name: "extensions.graphql", Input: `
+			extend interface User { email: String }
+		`}, &ast.Source{Name: "schema.graphql", Input: `
+			type Query { user: User }
+			type User { name: String }
+		`})
+		require.NotNil(t, err)
+		require.Equal(t, "Cannot extend type User because the base type is a OBJECT, not INTERFACE.", err.Message)
+		require.Equal(t, "extensions.graphql", err.Locations[0].Source)
+	})
+
+	t.Run("extensions of undefined types", func(t *testing.T) {
+		sources := []*ast.Source{Prelude, {Name: "schema.graphql", Input: `
+			type Query { a: Int }
+			extend type Ghost { x: Int }
+		`}}
+
+		// by default the extension defines the type, as federated schemas need
+		s, err := LoadSchemaWithOptions(sources)
+		require.Nil(t, err)
+		require.NotNil(t, s.Types["Ghost"])
+
+		_, err = LoadSchemaWithOptions(sources, WithStrictExtensions())
+		require.NotNil(t, err)
+		require.Equal(t, "Cannot extend type Ghost because it is not defined.", err.Message)
+		require.Equal(t, 3, err.Locations[0].Line)
+
+		// the base type may come from a later source
+		_, err = LoadSchemaWithOptions([]*ast.Source{Prelude, {Name: "ext.graphql", Input: `extend type Ghost { x: Int }`},
+			{Name: "schema.graphql", Input: `type Query { a: Int } type Ghost { y: Int }`}}, WithStrictExtensions())
+		require.Nil(t, err)
+	})
+
+	t.Run("types defined in two sources", func(t *testing.T) {
+		_, err := LoadSchema(Prelude,
+			&ast.Source{Name: "a.graphql", Input: "type Query {\n  user: User\n}\n"},
+			&ast.Source{Name: "b.graphql", Input: "type User {\n  id: ID!\n}\ntype Query {\n  me: User\n}\n"},
+		)
+		require.NotNil(t, err)
+		require.Equal(t, `Type "Query" defined in a.graphql and b.graphql.`, err.Message)
+		require.Equal(t, []gqlerror.Location{
+			{Line: 4, Column: 6, Source: "b.graphql", Start: 29, End: 34},
+			{Line: 1, Column: 6, Source: "a.graphql", Start: 5, End: 10},
+		}, err.Locations)
+
+		_, err = LoadSchema(Prelude, &ast.Source{Name: "schema.graphql", Input: "directive @skip on FIELD\ntype Query { a: Int }"})
+		require.NotNil(t, err)
+		require.Equal(t, `Directive "@skip" defined in prelude.graphql and schema.graphql.`, err.Message)
+	})
+
+	t.Run("schema directives", func(t *testing.T) {
+		s, err := LoadSchema(Prelude, &ast.Source{Input: `
+			directive @link(url: String!) on SCHEMA
+			directive @contact(name: String!) on SCHEMA
+			schema @contact(name: "team") { query: Query }
+			extend schema @link(url: "https://specs.apollo.dev/federation/v2.0")
+			type Query { name: String }
+		`, Name: "TestLoadSchema"})
+		require.Nil(t, err)
+
+		require.Equal(t, "Query", s.Query.Name)
+		require.Len(t, s.SchemaDirectives, 2)
+		require.Equal(t, "contact", s.SchemaDirectives[0].Name)
+		require.Equal(t, "link", s.SchemaDirectives[1].Name)
+		require.Equal(t, "https://specs.apollo.dev/federation/v2.0", s.SchemaDirectives.ForName("link").Arguments.ForName("url").Value.Raw)
+	})
+
 	testrunner.Test(t, "./schema_test.yml", func(t *testing.T, input string) testrunner.Spec {
 		_, err := LoadSchema(Prelude, &ast.Source{Input: input})
 		return testrunner.Spec{
@@ -63,3 +154,195 @@ func TestLoadSchema(t *testing.T) {
 		}
 	})
 }
+
+func TestCommentDescriptions(t *testing.T) {
+	input := &ast.Source{Name: "legacy.graphql", Input: `
+		# The query root
+		type Query {
+			# The name
+			name: String
+		}
+	`}
+
+	s, err := LoadSchemaWithOptions([]*ast.Source{Prelude, input}, WithCommentDescriptions())
+	require.Nil(t, err)
+	require.Equal(t, "The query root", s.Query.Description)
+	require.Equal(t, "The name", s.Query.Fields.ForName("name").Description)
+	require.Equal(t, "The `Boolean` scalar type represents `true` or `false`.", s.Types["Boolean"].Description)
+	// the notes in the prelude aren't descriptions
+	require.Equal(t, "", s.Types["__Type"].Fields.ForName("fields").Description)
+	require.Equal(t, "", s.Types["__Type"].Fields.ForName("ofType").Description)
+
+	s, err = LoadSchemaWithOptions([]*ast.Source{Prelude, input})
+	require.Nil(t, err)
+	require.Equal(t, "", s.Query.Description)
+	require.Equal(t, "", s.Query.Fields.ForName("name").Description)
+}
+
+func TestAllowedDirectives(t *testing.T) {
+	input := &ast.Source{Name: "schema.graphql", Input: `
+directive @allowed on FIELD_DEFINITION
+directive @custom on FIELD_DEFINITION
+
+type Query {
+	a: String @allowed @deprecated
+	b: String @custom
+}
+`}
+
+	t.Run("without option", func(t *testing.T) {
+		_, err := LoadSchemaWithOptions([]*ast.Source{Prelude, input})
+		require.Nil(t, err)
+	})
+
+	t.Run("disallowed directive", func(t *testing.T) {
+		_, err := LoadSchemaWithOptions([]*ast.Source{Prelude, input}, WithAllowedDirectives([]string{"allowed"}))
+		require.NotNil(t, err)
+		require.Equal(t, `Directive "@custom" is not permitted.`, err.Message)
+		require.Equal(t, 7, err.Locations[0].Line)
+	})
+
+	t.Run("allowed directive", func(t *testing.T) {
+		_, err := LoadSchemaWithOptions([]*ast.Source{Prelude, input}, WithAllowedDirectives([]string{"allowed", "custom"}))
+		require.Nil(t, err)
+	})
+}
+
+func TestSchemaErrorSource(t *testing.T) {
+	a := &ast.Source{Name: "a.graphql", Input: `type Query {
+	user: User
+	account: Account
+}
+`}
+	b := &ast.Source{Name: "b.graphql", Input: `type User {
+	name: String
+}
+`}
+
+	for _, order := range [][]*ast.Source{{Prelude, a, b}, {Prelude, b, a}} {
+		_, err := LoadSchema(order...)
+		require.NotNil(t, err)
+		require.Equal(t, "Undefined type Account.", err.Message)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 11, Source: "a.graphql", Start: 35, End: 42}}, err.Locations)
+		require.Equal(t, "a.graphql", err.Extensions["file"])
+		require.Equal(t, "a.graphql:3:11: Undefined type Account.", err.Error())
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	input := &ast.Source{Name: "latin1.graphql", Input: "type Query {\n  \"Le caf\xe9\"\n  name: String\n}\n"}
+
+	s, err := LoadSchemaWithOptions([]*ast.Source{Prelude, input}, WithEncoding(lexer.Latin1))
+	require.Nil(t, err)
+	require.Equal(t, "Le café", s.Query.Fields.ForName("name").Description)
+	require.Contains(t, s.Directives["deprecated"].Description, "GraphQL service’s schema")
+
+	s, err = LoadSchemaWithOptions([]*ast.Source{Prelude, input})
+	require.Nil(t, err)
+	require.Equal(t, "Le caf\xe9", s.Query.Fields.ForName("name").Description)
+}
+
+func TestAddDefinition(t *testing.T) {
+	s, err := LoadSchema(Prelude, &ast.Source{Name: "schema.graphql", Input: `
+		interface Node { id: ID! }
+		type Query { node(id: ID!): Node }
+	`})
+	require.Nil(t, err)
+
+	user := &ast.Definition{
+		Kind:       ast.Object,
+		Name:       "User",
+		Interfaces: []string{"Node"},
+		Fields: ast.FieldList{
+			{Name: "id", Type: ast.NonNullNamedType("ID", nil)},
+			{Name: "friends", Type: ast.ListType(ast.NamedType("User", nil), nil)},
+		},
+	}
+	require.Nil(t, AddDefinition(s, user))
+	require.Equal(t, user, s.Types["User"])
+	require.Equal(t, []*ast.Definition{user}, s.GetPossibleTypes(s.Types["Node"]))
+	require.Equal(t, []*ast.Definition{s.Types["Node"]}, s.GetImplements(user))
+
+	t.Run("redeclared", func(t *testing.T) {
+		err := AddDefinition(s, &ast.Definition{Kind: ast.Scalar, Name: "User"})
+		require.Equal(t, "Cannot redeclare type User.", err.Message)
+		require.Equal(t, user, s.Types["User"])
+	})
+
+	t.Run("invalid types leave the schema unchanged", func(t *testing.T) {
+		err := AddDefinition(s, &ast.Definition{
+			Kind:   ast.Object,
+			Name:   "Post",
+			Fields: ast.FieldList{{Name: "author", Type: ast.NamedType("Author", nil)}},
+		})
+		require.Equal(t, "Undefined type Author.", err.Message)
+		require.Empty(t, err.Locations)
+		require.Nil(t, s.Types["Post"])
+
+		err = AddDefinition(s, &ast.Definition{
+			Kind:       ast.Object,
+			Name:       "Group",
+			Interfaces: []string{"Node"},
+			Fields:     ast.FieldList{{Name: "name", Type: ast.NamedType("String", nil)}},
+		})
+		require.Equal(t, "For Group to implement Node it must have a field called id.", err.Message)
+		require.Nil(t, s.Types["Group"])
+		require.Len(t, s.GetPossibleTypes(s.Types["Node"]), 1)
+	})
+
+	t.Run("reserved enum values", func(t *testing.T) {
+		for _, name := range []string{"true", "false", "null"} {
+			err := AddDefinition(s, &ast.Definition{
+				Kind:       ast.Enum,
+				Name:       "Answer",
+				EnumValues: ast.EnumValueList{{Name: "maybe"}, {Name: name}},
+			})
+			require.Equal(t, `Name "`+name+`" is reserved and cannot be used for an enum value.`, err.Message)
+			require.Nil(t, s.Types["Answer"])
+		}
+	})
+
+	t.Run("directives", func(t *testing.T) {
+		tag := &ast.DirectiveDefinition{
+			Name:      "tag",
+			Arguments: ast.ArgumentDefinitionList{{Name: "name", Type: ast.NamedType("String", nil)}},
+			Locations: []ast.DirectiveLocation{ast.LocationObject},
+		}
+		require.Nil(t, AddDirective(s, tag))
+		require.Equal(t, tag, s.Directives["tag"])
+
+		err := AddDirective(s, &ast.DirectiveDefinition{Name: "tag", Locations: []ast.DirectiveLocation{ast.LocationField}})
+		require.Equal(t, "Cannot redeclare directive tag.", err.Message)
+		require.Equal(t, tag, s.Directives["tag"])
+
+		err = AddDirective(s, &ast.DirectiveDefinition{
+			Name:      "bad",
+			Arguments: ast.ArgumentDefinitionList{{Name: "in", Type: ast.NamedType("Missing", nil)}},
+			Locations: []ast.DirectiveLocation{ast.LocationObject},
+		})
+		require.NotNil(t, err)
+		require.Nil(t, s.Directives["bad"])
+	})
+}
+
+func TestExtensionAddsInterface(t *testing.T) {
+	s, err := LoadSchema(Prelude, &ast.Source{Name: "schema.graphql", Input: `
+		type Query { node: Node }
+		type User { name: String }
+		interface Node { id: ID! }
+		directive @key(fields: String!) on OBJECT
+	`}, &ast.Source{Name: "extensions.graphql", Input: `
+		extend type User implements Node @key(fields: "id") {
+			id: ID!
+		}
+	`})
+	require.Nil(t, err)
+
+	user := s.Types["User"]
+	require.Equal(t, []string{"Node"}, user.Interfaces)
+	require.NotNil(t, user.Fields.ForName("name"))
+	require.NotNil(t, user.Fields.ForName("id"))
+	require.NotNil(t, user.Directives.ForName("key"))
+	require.Equal(t, []*ast.Definition{user}, s.GetPossibleTypes(s.Types["Node"]))
+	require.Equal(t, []*ast.Definition{s.Types["Node"]}, s.GetImplements(user))
+}
@@ -0,0 +1,44 @@
+package validator
+
+import (
+	. "github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
+	"github.com/dgraph-io/gqlparser/v2/parser"
+)
+
+// QueryStream validates a query document that arrives one token at a time, eg from an editor as the user types.
+// Each top level operation or fragment is parsed by a parser.QueryStream and validated on its own as soon as its
+// closing brace is pushed. KnownFragmentNames and NoUnusedFragments are left out, since a fragment is often spread
+// from, or defined in, a different definition, so validate the whole document once it's complete to check them.
+type QueryStream struct {
+	schema *Schema
+	rules  *RuleSet
+	stream *parser.QueryStream
+}
+
+// NewQueryStream creates a QueryStream that validates against schema with the globally registered rules.
+func NewQueryStream(schema *Schema) *QueryStream {
+	rules := DefaultRuleSet()
+	rules.RemoveRule("KnownFragmentNames")
+	rules.RemoveRule("NoUnusedFragments")
+	return &QueryStream{
+		schema: schema,
+		rules:  rules,
+		stream: parser.NewQueryStream(),
+	}
+}
+
+// Push adds the next token. When it completes a top level definition, that definition is returned in a document
+// of its own along with the errors from validating it, or a nil document and the syntax error if it doesn't
+// parse. Otherwise both are nil.
+func (s *QueryStream) Push(tok lexer.Token) (*QueryDocument, gqlerror.List) {
+	doc, err := s.stream.Push(tok)
+	if err != nil {
+		return nil, gqlerror.List{err}
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return doc, validate(s.schema, doc, nil, s.rules)
+}
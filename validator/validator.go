@@ -1,56 +1,194 @@
 package validator
 
 import (
+	"fmt"
 	"sort"
+	"sync"
 
 	. "github.com/dgraph-io/gqlparser/v2/ast"
 	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/parser"
 )
 
 type AddErrFunc func(options ...ErrorOption)
 
-type ruleFunc func(observers *Events, addError AddErrFunc)
+// RuleFunc registers the observers for a rule. It is called once each time a document is validated.
+type RuleFunc func(observers *Events, addError AddErrFunc)
 
-type rule struct {
-	name string
+type Rule struct {
+	Name string
 	// rules will be called in the ascending order
-	order int
-	rule  ruleFunc
+	Order    int
+	RuleFunc RuleFunc
 }
 
-var rules []rule
+// RuleSet is an ordered collection of rules that can be passed to ValidateWithRules.
+//
+// Validate runs the rules registered with AddRule, which once validator/rules is imported are the rules of the
+// spec. validator/rules also has opt in rules, eg NoIntrospection or MaxFields, which are configured or go beyond
+// the spec, so they are returned by functions instead of being registered. To use them add them to a RuleSet,
+// usually one from DefaultRuleSet, and validate with ValidateWithRules.
+type RuleSet struct {
+	rules []Rule
+}
+
+// NewRuleSet creates a RuleSet containing exactly the given rules.
+func NewRuleSet(rules ...Rule) *RuleSet {
+	rs := &RuleSet{}
+	for _, r := range rules {
+		rs.AddRule(r)
+	}
+	return rs
+}
+
+// DefaultRuleSet returns a copy of the globally registered rules used by Validate.
+// Changes to the returned set do not affect the global rules.
+func DefaultRuleSet() *RuleSet {
+	return &RuleSet{rules: append([]Rule{}, defaultRules.rules...)}
+}
+
+// AddRule to the set, keeping rules sorted by their order.
+func (rs *RuleSet) AddRule(rule Rule) {
+	rs.rules = append(rs.rules, rule)
+	sort.SliceStable(rs.rules, func(i, j int) bool {
+		return rs.rules[i].Order < rs.rules[j].Order
+	})
+}
+
+// RemoveRule removes all rules with the given name from the set.
+func (rs *RuleSet) RemoveRule(name string) {
+	rules := rs.rules[:0]
+	for _, r := range rs.rules {
+		if r.Name != name {
+			rules = append(rules, r)
+		}
+	}
+	rs.rules = rules
+}
+
+// Rules returns the rules in the set in the order they will be run.
+func (rs *RuleSet) Rules() []Rule {
+	return append([]Rule{}, rs.rules...)
+}
+
+var defaultRules RuleSet
 
 // addRule to rule set.
 // f is called once each time `Validate` is executed.
-func AddRule(name string, f ruleFunc) {
-	rules = append(rules, rule{name: name, rule: f})
+func AddRule(name string, f RuleFunc) {
+	defaultRules.rules = append(defaultRules.rules, Rule{Name: name, RuleFunc: f})
 }
 
 // AddRuleWithOrder to rule set with an order.
 // f is called once each time `Validate` is executed.
-func AddRuleWithOrder(name string, order int, f ruleFunc) {
-	rules = append(rules, rule{name: name, order: order, rule: f})
-	sort.Slice(rules, func(i, j int) bool {
-		return rules[i].order < rules[j].order
-	})
+func AddRuleWithOrder(name string, order int, f RuleFunc) {
+	defaultRules.AddRule(Rule{Name: name, Order: order, RuleFunc: f})
 }
 
 func Validate(schema *Schema, doc *QueryDocument, variables map[string]interface{}) gqlerror.List {
+	return validate(schema, doc, variables, &defaultRules)
+}
+
+// LoadQuery parses a query and validates it with the rules in ruleSet in one step. If it doesn't parse the
+// document is nil and the list holds the parse error. Otherwise the document is always returned, along with any
+// validation errors, so callers can still inspect an invalid query. Pass DefaultRuleSet() for the globally
+// registered rules, which only hold the standard ones once validator/rules is imported.
+func LoadQuery(schema *Schema, query string, ruleSet *RuleSet) (*QueryDocument, gqlerror.List) {
+	doc, err := parser.ParseQuery(&Source{Input: query})
+	if err != nil {
+		return nil, gqlerror.List{err}
+	}
+	return doc, ValidateWithRules(schema, doc, ruleSet)
+}
+
+// ValidateWithRules validates doc using only the rules in ruleSet, instead of the globally registered rules.
+func ValidateWithRules(schema *Schema, doc *QueryDocument, ruleSet *RuleSet) gqlerror.List {
+	return validate(schema, doc, nil, ruleSet)
+}
+
+// schemaIndependentRules only check the structure of a document, or how the built in directives are used, so
+// they give meaningful results before a schema is available.
+var schemaIndependentRules = map[string]bool{
+	"KnownDirectives":             true,
+	"KnownFragmentNames":          true,
+	"LoneAnonymousOperation":      true,
+	"NoFragmentCycles":            true,
+	"NoUndefinedVariables":        true,
+	"NoUnusedFragments":           true,
+	"NoUnusedVariables":           true,
+	"SingleFieldSubscriptions":    true,
+	"UniqueArgumentNames":         true,
+	"UniqueDirectivesPerLocation": true,
+	"UniqueFragmentNames":         true,
+	"UniqueInputFieldNames":       true,
+	"UniqueOperationNames":        true,
+	"UniqueVariableNames":         true,
+}
+
+// IsSchemaIndependent reports whether the named rule is run by ValidateWithoutSchema.
+func IsSchemaIndependent(ruleName string) bool {
+	return schemaIndependentRules[ruleName]
+}
+
+var (
+	preludeSchema     *Schema
+	preludeSchemaOnce sync.Once
+)
+
+// ValidateWithoutSchema validates doc with only the schema independent rules, eg fragment cycles, unused
+// variables and the placement of @skip and @include, for linting before the schema is known. Only the built in
+// directives are known, any other directive is reported as unknown.
+func ValidateWithoutSchema(doc *QueryDocument) gqlerror.List {
+	preludeSchemaOnce.Do(func() {
+		var err *gqlerror.Error
+		preludeSchema, err = LoadSchema(Prelude)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	ruleSet := &RuleSet{}
+	for _, rule := range defaultRules.rules {
+		if schemaIndependentRules[rule.Name] {
+			ruleSet.rules = append(ruleSet.rules, rule)
+		}
+	}
+	return validate(preludeSchema, doc, nil, ruleSet)
+}
+
+func validate(schema *Schema, doc *QueryDocument, variables map[string]interface{}, ruleSet *RuleSet) gqlerror.List {
 	var errs gqlerror.List
+	// fragments are walked on their own and again for each operation spreading them, so rules see the selections
+	// in them several times. An error that was already found while walking an earlier operation or fragment is
+	// left out, errors repeated within one walk are kept as they are about different uses of the same thing.
+	walks := 0
+	reported := map[string]int{}
 
 	observers := &Events{}
-	for i := range rules {
-		rule := rules[i]
-		rule.rule(observers, func(options ...ErrorOption) {
+	for i := range ruleSet.rules {
+		rule := ruleSet.rules[i]
+		rule.RuleFunc(observers, func(options ...ErrorOption) {
 			err := &gqlerror.Error{
-				Rule: rule.name,
+				Rule: rule.Name,
 			}
 			for _, o := range options {
 				o(err)
 			}
+			key := fmt.Sprint(err.Rule, err.Message, err.Locations)
+			if walk, ok := reported[key]; ok && walk != walks {
+				return
+			}
+			reported[key] = walks
 			errs = append(errs, err)
 		})
 	}
+	// registered after the rules, so errors added at the end of a walk still count as part of it
+	observers.OnOperation(func(walker *Walker, operation *OperationDefinition) {
+		walks++
+	})
+	observers.OnFragment(func(walker *Walker, fragment *FragmentDefinition) {
+		walks++
+	})
 
 	Walk(schema, doc, observers, variables)
 	return errs
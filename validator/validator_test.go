@@ -1,12 +1,17 @@
 package validator_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/dgraph-io/gqlparser/v2"
 	"github.com/dgraph-io/gqlparser/v2/ast"
+	"github.com/dgraph-io/gqlparser/v2/gqlerror"
+	"github.com/dgraph-io/gqlparser/v2/lexer"
 	"github.com/dgraph-io/gqlparser/v2/parser"
 	"github.com/dgraph-io/gqlparser/v2/validator"
+	rules "github.com/dgraph-io/gqlparser/v2/validator/rules"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +44,613 @@ extend type Query {
 	require.Nil(t, err)
 	require.Nil(t, validator.Validate(s, q, nil))
 }
+
+func TestValidateWithRules(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query {
+	name: String
+}
+`})
+
+	q, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `{ name age }`})
+	require.Nil(t, err)
+
+	t.Run("default rules", func(t *testing.T) {
+		errs := validator.ValidateWithRules(s, q, validator.DefaultRuleSet())
+		require.Len(t, errs, 1)
+		require.Equal(t, "FieldsOnCorrectType", errs[0].Rule)
+	})
+
+	t.Run("without built in rule", func(t *testing.T) {
+		rs := validator.DefaultRuleSet()
+		rs.RemoveRule("FieldsOnCorrectType")
+		require.Nil(t, validator.ValidateWithRules(s, q, rs))
+
+		// the global rules are unaffected
+		require.Len(t, validator.Validate(s, q, nil), 1)
+	})
+
+	t.Run("custom rule", func(t *testing.T) {
+		rs := validator.NewRuleSet(validator.Rule{
+			Name: "NoAge",
+			RuleFunc: func(observers *validator.Events, addError validator.AddErrFunc) {
+				observers.OnField(func(walker *validator.Walker, field *ast.Field) {
+					if field.Name == "age" {
+						addError(validator.Message("age is not allowed"), validator.At(field.Position))
+					}
+				})
+			},
+		})
+
+		errs := validator.ValidateWithRules(s, q, rs)
+		require.Len(t, errs, 1)
+		require.Equal(t, "NoAge", errs[0].Rule)
+		require.Equal(t, "age is not allowed", errs[0].Message)
+	})
+}
+
+func TestVariablesInAllowedPosition(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Query {
+	count(n: Int!): Int
+	sum(ns: [Int!]!): Int
+}
+`})
+
+	validate := func(query string) gqlerror.List {
+		q, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+		require.Nil(t, err)
+		return validator.Validate(s, q, nil)
+	}
+
+	require.Nil(t, validate(`query($n: Int = 1) { count(n: $n) }`))
+	require.Nil(t, validate(`query($n: Int!) { sum(ns: [$n]) }`))
+	require.Nil(t, validate(`query($ns: [Int!]!) { sum(ns: $ns) }`))
+
+	// a default value on one query must not relax the check for the next
+	errs := validate(`query($n: Int) { count(n: $n) }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Variable "$n" of type "Int" used in position expecting type "Int!".`, errs[0].Message)
+
+	errs = validate(`query($n: Int) { sum(ns: [$n]) }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Variable "$n" of type "Int" used in position expecting type "Int!".`, errs[0].Message)
+
+	errs = validate(`query($ns: [Int]!) { sum(ns: $ns) }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Variable "$ns" of type "[Int]!" used in position expecting type "[Int!]!".`, errs[0].Message)
+}
+
+func TestNoSideEffectsInQueries(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		directive @mutation on FIELD_DEFINITION
+		type Query {
+			user: User
+		}
+		type Mutation {
+			user: User
+		}
+		type User {
+			name: String
+			resetPassword: Boolean @mutation
+		}
+	`})
+	ruleSet := validator.NewRuleSet(rules.NoSideEffectsInQueries("mutation"))
+
+	// the fragment is fine in the mutation, only its use in the query is reported
+	errs := validateQuery(t, s, ruleSet, `
+		query Q { user { ...UserFields } }
+		mutation M { user { ...UserFields } }
+		fragment UserFields on User { name resetPassword }
+	`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Field "User.resetPassword" is marked @mutation and cannot be selected in a query operation.`, errs[0].Message)
+	require.Equal(t, "NoSideEffectsInQueries", errs[0].Rule)
+	require.Equal(t, 4, errs[0].Locations[0].Line)
+
+	errs = validateQuery(t, s, ruleSet, `{ user { ... on User { resetPassword } } }`)
+	require.Len(t, errs, 1)
+
+	require.Empty(t, validateQuery(t, s, ruleSet, `mutation { user { name resetPassword } }`))
+	require.Empty(t, validateQuery(t, s, ruleSet, `query { user { name } }`))
+}
+
+func TestValidateWithoutSchema(t *testing.T) {
+	q, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `
+		query Users($unused: Int, $first: Int) {
+			users(first: $first, after: $cursor) @skip(if: true) {
+				...UserFields
+				name(format: "short", format: "long")
+			}
+		}
+		fragment UserFields on User @include(if: true) {
+			...UserFields
+		}
+	`})
+	require.Nil(t, err)
+
+	var messages []string
+	for _, err := range validator.ValidateWithoutSchema(q) {
+		messages = append(messages, err.Message)
+		require.True(t, validator.IsSchemaIndependent(err.Rule), err.Rule)
+	}
+	require.ElementsMatch(t, []string{
+		`Variable "$unused" is never used in operation "Users".`,
+		`Variable "$cursor" is not defined by operation "Users".`,
+		`There can be only one argument named "format".`,
+		`Directive "include" may not be used on FRAGMENT_DEFINITION.`,
+		`Cannot spread fragment "UserFields" within itself.`,
+	}, messages)
+
+	q, err = parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `{ users { name @skip(if: true) } }`})
+	require.Nil(t, err)
+	require.Empty(t, validator.ValidateWithoutSchema(q))
+}
+
+func TestDeferStreamDirectiveOnValidOperations(t *testing.T) {
+	s, gerr := validator.LoadSchema(validator.Prelude, validator.DeferStream, &ast.Source{Name: "schema.graphql", Input: `
+		type Query {
+			user: User
+		}
+		type Subscription {
+			user: User
+		}
+		type User {
+			name: String
+			friends: [User]
+		}
+	`})
+	require.Nil(t, gerr)
+
+	ruleSet := validator.DefaultRuleSet()
+	ruleSet.AddRule(rules.DeferStreamDirectiveOnValidOperations())
+
+	// the messages, as every directive is checked on its own
+	validate := func(query string) []string {
+		var messages []string
+		for _, err := range validateQuery(t, s, ruleSet, query) {
+			messages = append(messages, err.Message)
+		}
+		return messages
+	}
+
+	require.Empty(t, validate(`
+		query ($label: Boolean!) {
+			user {
+				... @defer(label: "a") { name }
+				...UserFriends @defer(if: $label, label: "b")
+			}
+		}
+		fragment UserFriends on User { friends @stream(initialCount: 2, label: "c") { name } }
+	`))
+
+	require.Equal(t, []string{
+		`Defer directive not supported on subscription operations. Disable "@defer" by setting the "if" argument to "false".`,
+		`Stream directive not supported on subscription operations. Disable "@stream" by setting the "if" argument to "false".`,
+	}, validate(`subscription {
+		user {
+			... @defer { name }
+			... @defer(if: false) { name }
+			friends @stream(if: true) { name }
+		}
+	}`))
+
+	require.ElementsMatch(t, []string{
+		`Defer/Stream directive label argument must be unique.`,
+		`Directive "stream"'s label argument must be a static string.`,
+		`Stream directive initialCount argument must be a non-negative integer, found -1.`,
+		`Stream directive cannot be used on non-list field "name" on type "User".`,
+	}, validate(`
+		query ($label: String) {
+			user {
+				... @defer(label: "a") { name @stream }
+				...UserFriends
+			}
+		}
+		fragment UserFriends on User {
+			... @defer(label: "a") { friends @stream(label: $label, initialCount: -1) { name } }
+		}
+	`))
+
+	t.Run("without opting in", func(t *testing.T) {
+		s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query { names: [String] }`})
+		errs := validateQuery(t, s, ruleSet, `{ names @stream }`)
+		require.Len(t, errs, 1)
+		require.Equal(t, `Unknown directive "stream".`, errs[0].Message)
+	})
+}
+
+func TestNoIntrospection(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query {
+			user: User
+		}
+		type User {
+			name: String
+		}
+	`})
+
+	// introspection is found through fragments, but not in fragments that are never spread
+	errs := validateQuery(t, s, validator.NewRuleSet(rules.NoIntrospection(true)), `{
+		__schema { queryType { name } }
+		user { __typename name }
+		...RootFields
+	}
+	fragment RootFields on Query { ... { __type(name: "User") { name } } }
+	fragment Unused on Query { __schema { types { name } } }`)
+	require.Len(t, errs, 2)
+	for _, err := range errs {
+		require.Equal(t, `GraphQL introspection is not allowed.`, err.Message)
+		require.Equal(t, "NoIntrospection", err.Rule)
+	}
+	require.Equal(t, 2, errs[0].Locations[0].Line)
+	require.Equal(t, 6, errs[1].Locations[0].Line)
+
+	require.Empty(t, validateQuery(t, s, validator.NewRuleSet(rules.NoIntrospection(true)), `{ __typename user { __typename name } }`))
+
+	errs = validateQuery(t, s, validator.NewRuleSet(rules.NoIntrospection(false)), `{ user { __typename name } }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, 1, errs[0].Locations[0].Line)
+}
+
+func TestValidationErrorPaths(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "graph/schema.graphqls", Input: `
+		type Query { user(id: ID!): User }
+		type User { name: String, friends: [User] }
+	`})
+
+	q, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `{
+		user(id: 1) {
+			friends {
+				best: friends
+				nam
+			}
+		}
+	}`})
+	require.Nil(t, err)
+
+	errs := validator.Validate(s, q, nil)
+	require.Len(t, errs, 2)
+	require.Equal(t, ast.Path{ast.PathName("user"), ast.PathName("friends"), ast.PathName("best")}, errs[0].Path)
+	require.Equal(t, "ScalarLeafs", errs[0].Rule)
+	require.Equal(t, ast.Path{ast.PathName("user"), ast.PathName("friends"), ast.PathName("nam")}, errs[1].Path)
+	require.Equal(t, "FieldsOnCorrectType", errs[1].Rule)
+}
+
+func TestFragmentErrorsReportedOnce(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query { user(id: ID): User }
+		type User { name: String }
+	`})
+
+	// F is walked for A, for B and on its own, each of its errors is reported once
+	errs := validateQuery(t, s, validator.DefaultRuleSet(), `
+		query A { ...F }
+		query B { ...F }
+		fragment F on Query { user(id: 1, id: 2) { nam } }
+	`)
+	require.Len(t, errs, 2)
+	require.Equal(t, "FieldsOnCorrectType", errs[0].Rule)
+	require.Equal(t, ast.Path{ast.PathName("user"), ast.PathName("nam")}, errs[0].Path)
+	require.Equal(t, "UniqueArgumentNames", errs[1].Rule)
+}
+
+func TestLoadQuery(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { name: String }`})
+
+	doc, errs := validator.LoadQuery(s, `{ name }`, validator.DefaultRuleSet())
+	require.Empty(t, errs)
+	require.Len(t, doc.Operations, 1)
+
+	doc, errs = validator.LoadQuery(s, `{ nam }`, validator.DefaultRuleSet())
+	require.NotNil(t, doc)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Cannot query field "nam" on type "Query". Did you mean "name"?`, errs[0].Message)
+
+	doc, errs = validator.LoadQuery(s, `{ name`, validator.DefaultRuleSet())
+	require.Nil(t, doc)
+	require.Len(t, errs, 1)
+	require.Equal(t, "Expected Name, found <EOF>", errs[0].Message)
+}
+
+func TestOneOfVariables(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		directive @oneOfVariables(variables: [String!]!) on QUERY | MUTATION
+		type Query {
+			user(id: ID, name: String): User
+		}
+		type User {
+			name: String
+		}
+	`})
+
+	// with the default rules, which check the directive's own arguments
+	ruleSet := validator.DefaultRuleSet()
+	ruleSet.AddRule(rules.OneOfVariables("oneOfVariables"))
+	validate := func(query string) gqlerror.List {
+		return validateQuery(t, s, ruleSet, query)
+	}
+
+	require.Empty(t, validate(`query Find($id: ID, $name: String) @oneOfVariables(variables: ["id", "name"]) {
+		user(id: $id, name: $name) { name }
+	}`))
+	require.Empty(t, validate(`query Find($id: ID = 1, $name: String = null) @oneOfVariables(variables: ["id", "name"]) {
+		user(id: $id, name: $name) { name }
+	}`))
+
+	errs := validate(`query Find($id: ID, $name: String) @oneOfVariables(variables: ["id", "nmae"]) {
+		user(id: $id, name: $name) { name }
+	}`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Unknown variable "$nmae" in @oneOfVariables on operation "Find".`, errs[0].Message)
+	require.Equal(t, "OneOfVariables", errs[0].Rule)
+	require.Equal(t, 1, errs[0].Locations[0].Line)
+
+	errs = validate(`query ($id: ID!, $name: String) @oneOfVariables(variables: "id") {
+		user(id: $id, name: $name) { name }
+	}`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Variable "$id" must be nullable to be used in @oneOfVariables.`, errs[0].Message)
+
+	errs = validate(`query ($id: ID = 1, $name: String = "a") @oneOfVariables(variables: ["id", "name"]) {
+		user(id: $id, name: $name) { name }
+	}`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Only one of the variables in @oneOfVariables can have a non-null default value, found defaults for "$id" and "$name".`, errs[0].Message)
+
+	// the directive itself is still checked by the default rules
+	errs = validate(`query ($id: ID) @oneOfVariables { user(id: $id) { name } }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, "ProvidedRequiredArguments", errs[0].Rule)
+}
+
+func TestCustomOneOfDirective(t *testing.T) {
+	// without the prelude a schema can define a @oneOf of its own, which doesn't get the built in semantics
+	s, err := validator.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		directive @oneOf on INPUT_OBJECT
+		scalar String
+		input Filter @oneOf { a: String b: String! }
+		type Query { find(filter: Filter): String }
+	`})
+	require.Nil(t, err)
+	require.False(t, s.IsOneOf(s.Types["Filter"]))
+
+	q, perr := parser.ParseQuery(&ast.Source{Input: `{ find(filter: {a: "x", b: "y"}) }`})
+	require.Nil(t, perr)
+	require.Empty(t, validator.Validate(s, q, nil))
+
+	builtin := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		input Filter @oneOf { a: String b: String }
+		type Query { find(filter: Filter): String }
+	`})
+	require.True(t, builtin.IsOneOf(builtin.Types["Filter"]))
+}
+
+func TestDefaultValuesOfCorrectType(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query {
+			f(int: Int, ints: [Int!], filter: Filter, role: Role, choice: Choice, id: ID, time: Time): Int
+		}
+		input Filter {
+			name: String!
+			first: Int! = 10
+			tags: [String]
+		}
+		input Choice @oneOf { a: Int b: String }
+		enum Role { ADMIN GUEST }
+		scalar Time
+	`})
+
+	ruleSet := validator.NewRuleSet(rules.DefaultValuesOfCorrectType())
+
+	// validates a query with a single variable definition
+	validate := func(variable string) gqlerror.List {
+		return validateQuery(t, s, ruleSet, `query (`+variable+`) { f }`)
+	}
+
+	for _, valid := range []string{
+		`$x: Int = 1`,
+		`$x: Int`,
+		`$x: Int = null`,
+		`$x: Float = 1`,
+		`$x: ID = 1`,
+		`$x: ID = "a"`,
+		`$x: [Int!] = [1, 2]`,
+		`$x: [Int!] = 1`,
+		`$x: [[Int]] = [[1], null, 2]`,
+		`$x: Filter = {name: "a"}`,
+		`$x: Filter = {name: "a", first: 1, tags: "t"}`,
+		`$x: Role = ADMIN`,
+		`$x: Choice = {b: "x"}`,
+		`$x: Time = "2020-01-01"`,
+		`$x: Time = 12`,
+	} {
+		require.Empty(t, validate(valid), valid)
+	}
+
+	for invalid, message := range map[string]string{
+		`$x: Int = "hello"`:                   `Variable "$x" of type "Int" has invalid default value "hello".`,
+		`$x: Int = 1.5`:                       `Variable "$x" of type "Int" has invalid default value 1.5.`,
+		`$x: Int! = null`:                     `Variable "$x" of type "Int!" has invalid default value null.`,
+		`$x: [Int!] = [1, null]`:              `Variable "$x" of type "[Int!]" has invalid default value [1,null].`,
+		`$x: [Int!]! = ["a"]`:                 `Variable "$x" of type "[Int!]!" has invalid default value ["a"].`,
+		`$x: Filter = {first: 1}`:             `Variable "$x" of type "Filter" has invalid default value {first:1}.`,
+		`$x: Filter = {name: "a", age: 1}`:    `Variable "$x" of type "Filter" has invalid default value {name:"a",age:1}.`,
+		`$x: Filter = {name: "a", tags: [1]}`: `Variable "$x" of type "Filter" has invalid default value {name:"a",tags:[1]}.`,
+		`$x: Filter = "a"`:                    `Variable "$x" of type "Filter" has invalid default value "a".`,
+		`$x: Role = OWNER`:                    `Variable "$x" of type "Role" has invalid default value OWNER.`,
+		`$x: Role = "ADMIN"`:                  `Variable "$x" of type "Role" has invalid default value "ADMIN".`,
+		`$x: Choice = {a: 1, b: "x"}`:         `Variable "$x" of type "Choice" has invalid default value {a:1,b:"x"}.`,
+		`$x: Boolean = 0`:                     `Variable "$x" of type "Boolean" has invalid default value 0.`,
+	} {
+		errs := validate(invalid)
+		require.Len(t, errs, 1, invalid)
+		require.Equal(t, message, errs[0].Message, invalid)
+		require.Equal(t, "DefaultValuesOfCorrectType", errs[0].Rule)
+		require.Equal(t, 1, errs[0].Locations[0].Line)
+	}
+}
+
+func TestMaxFieldsAndAliases(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query {
+			user: User
+		}
+		type User {
+			name: String
+			friends: [User]
+		}
+	`})
+
+	validate := func(rule validator.Rule, query string) gqlerror.List {
+		return validateQuery(t, s, validator.NewRuleSet(rule), query)
+	}
+
+	query := `
+		query Users {
+			a: user { ...F }
+			b: user { ...F }
+			user { ... on User { name } }
+		}
+		fragment F on User { name n: name friends { ...G } }
+		fragment G on User { name }
+	`
+
+	// 3 root fields, 4 in each spread of F and 1 in the inline fragment
+	require.Empty(t, validate(rules.MaxFields(12), query))
+	errs := validate(rules.MaxFields(11), query)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Operation selects 12 fields, more than the limit of 11.`, errs[0].Message)
+	require.Equal(t, "MaxFields", errs[0].Rule)
+	require.Equal(t, 2, errs[0].Locations[0].Line)
+
+	// a, b and one n in each spread of F
+	require.Empty(t, validate(rules.MaxAliases(4), query))
+	errs = validate(rules.MaxAliases(3), query)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Operation uses 4 aliases, more than the limit of 3.`, errs[0].Message)
+	require.Equal(t, "MaxAliases", errs[0].Rule)
+
+	// aliasing a field to its own name isn't counted
+	require.Empty(t, validate(rules.MaxAliases(0), `{ user: user { name: name } }`))
+
+	// 64 fragments each spreading the previous one twice select 2^64 fields, which must not wrap around to 0
+	doubling := "{ user { ...F0 } }\nfragment F0 on User { name }\n"
+	for i := 1; i < 64; i++ {
+		doubling += fmt.Sprintf("fragment F%d on User { a: friends { ...F%d } b: friends { ...F%d } }\n", i, i-1, i-1)
+	}
+	doubling = strings.Replace(doubling, "...F0 } }", "...F63 } }", 1)
+	errs = validate(rules.MaxFields(100), doubling)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Operation selects at least 2147483647 fields, more than the limit of 100.`, errs[0].Message)
+
+	// cycles are reported by NoFragmentCycles, they mustn't hang the count
+	require.Empty(t, validate(rules.MaxFields(10), `
+		{ user { ...A } }
+		fragment A on User { name friends { ...B } }
+		fragment B on User { friends { ...A } }
+	`))
+}
+
+func TestCustomRootOperationTypes(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		schema {
+			query: RootQuery
+			mutation: RootMutation
+		}
+		type RootQuery { user: User }
+		type RootMutation { rename(name: String!): User }
+		type User { name: String }
+
+		# only roots through the schema definition
+		type Query { unrelated: Int }
+		type Subscription { ticks: Int }
+	`})
+
+	require.Equal(t, "RootQuery", s.Query.Name)
+	require.Equal(t, "RootMutation", s.Mutation.Name)
+	require.Nil(t, s.Subscription)
+	require.NotNil(t, s.Query.Fields.ForName("__schema"))
+	require.Nil(t, s.Types["Query"].Fields.ForName("__schema"))
+
+	_, errs := gqlparser.LoadQuery(s, `{
+		__typename
+		__schema { queryType { name } mutationType { name } }
+		__type(name: "User") { name }
+		user { name }
+	}`)
+	require.Empty(t, errs)
+
+	_, errs = gqlparser.LoadQuery(s, `mutation { __typename rename(name: "a") { name } }`)
+	require.Empty(t, errs)
+
+	_, errs = gqlparser.LoadQuery(s, `{ unrelated }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Cannot query field "unrelated" on type "RootQuery".`, errs[0].Message)
+
+	_, errs = gqlparser.LoadQuery(s, `mutation { user { name } }`)
+	require.Len(t, errs, 1)
+	require.Equal(t, `Cannot query field "user" on type "RootMutation".`, errs[0].Message)
+}
+
+func TestQueryStream(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query { user: User }
+		type User { name: String friends: [User] }
+	`})
+
+	type result struct {
+		doc  *ast.QueryDocument
+		errs gqlerror.List
+	}
+	push := func(input string) []result {
+		l := lexer.New(&ast.Source{Name: "query.graphql", Input: input})
+		stream := validator.NewQueryStream(s)
+		var results []result
+		for {
+			tok, err := l.ReadToken()
+			require.Nil(t, err)
+			doc, errs := stream.Push(tok)
+			if doc != nil || errs != nil {
+				results = append(results, result{doc, errs})
+			}
+			if tok.Kind == lexer.EOF {
+				return results
+			}
+		}
+	}
+
+	results := push(`
+		query A { user { ...Friends } }
+		query B { user { email } }
+		fragment Friends on User { friends { name } }
+	`)
+	require.Len(t, results, 3)
+
+	// the fragment spread in A is only defined later, which isn't an error until the document is complete
+	require.Equal(t, "A", results[0].doc.Operations[0].Name)
+	require.Empty(t, results[0].errs)
+
+	require.Equal(t, "B", results[1].doc.Operations[0].Name)
+	require.Len(t, results[1].errs, 1)
+	require.Equal(t, `Cannot query field "email" on type "User".`, results[1].errs[0].Message)
+	require.Equal(t, 3, results[1].errs[0].Locations[0].Line)
+
+	require.Equal(t, "Friends", results[2].doc.Fragments[0].Name)
+	require.Empty(t, results[2].errs)
+
+	results = push("{ user { name } }\n{ user( }")
+	require.Len(t, results, 2)
+	require.Empty(t, results[0].errs)
+	require.Nil(t, results[1].doc)
+	require.Len(t, results[1].errs, 1)
+	require.Equal(t, "Expected Name, found }", results[1].errs[0].Message)
+}
+
+// validateQuery parses query and validates it against schema with the rules in ruleSet.
+func validateQuery(t *testing.T, schema *ast.Schema, ruleSet *validator.RuleSet, query string) gqlerror.List {
+	t.Helper()
+	q, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+	require.Nil(t, err)
+	return validator.ValidateWithRules(schema, q, ruleSet)
+}
@@ -16,6 +16,9 @@ type Events struct {
 	directive        []func(walker *Walker, directive *ast.Directive)
 	directiveList    []func(walker *Walker, directives []*ast.Directive)
 	value            []func(walker *Walker, value *ast.Value)
+
+	selectionSet      []func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet)
+	selectionSetLeave []func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet)
 }
 
 func (o *Events) OnOperation(f func(walker *Walker, operation *ast.OperationDefinition)) {
@@ -43,6 +46,18 @@ func (o *Events) OnValue(f func(walker *Walker, value *ast.Value)) {
 	o.value = append(o.value, f)
 }
 
+// OnSelectionSet is called before walking the selections of every non empty selection set, including those of
+// inline fragments and of fragment definitions expanded at a spread. parentDef is the type the selections are
+// made on, and is nil when it's unknown.
+func (o *Events) OnSelectionSet(f func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet)) {
+	o.selectionSet = append(o.selectionSet, f)
+}
+
+// OnSelectionSetLeave is called after walking the selections of every selection set OnSelectionSet was called for.
+func (o *Events) OnSelectionSetLeave(f func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet)) {
+	o.selectionSetLeave = append(o.selectionSetLeave, f)
+}
+
 func Walk(schema *ast.Schema, document *ast.QueryDocument, observers *Events, variables map[string]interface{}) {
 	w := Walker{
 		Observers: observers,
@@ -62,15 +77,32 @@ type Walker struct {
 	Variables                map[string]interface{} // These variables are not coerced
 	validatedFragmentSpreads map[string]bool
 	CurrentOperation         *ast.OperationDefinition
+	// the response keys of the fields being walked
+	path ast.Path
+}
+
+// Path returns the response path of the field being walked, made of the alias or name of it and each field it
+// is nested in. Validation doesn't know how many items lists will return, so unlike execution paths it never
+// contains indices. Fragments spread into an operation continue the path of the spread, and as a fragment is only
+// walked at its first spread in an operation, that is the only path it's seen at. Fragments walked on their own,
+// outside of any operation, have no response path, so Path returns nil while CurrentOperation is nil. The returned
+// path is a copy that can be kept.
+func (w *Walker) Path() ast.Path {
+	if w.CurrentOperation == nil {
+		return nil
+	}
+	return append(ast.Path{}, w.path...)
 }
 
 func (w *Walker) walk() {
 	for _, child := range w.Document.Operations {
 		w.validatedFragmentSpreads = make(map[string]bool)
+		w.path = nil
 		w.walkOperation(child)
 	}
 	for _, child := range w.Document.Fragments {
 		w.validatedFragmentSpreads = make(map[string]bool)
+		w.path = nil
 		w.walkFragment(child)
 	}
 }
@@ -78,11 +110,11 @@ func (w *Walker) walk() {
 func (w *Walker) walkOperation(operation *ast.OperationDefinition) {
 	w.CurrentOperation = operation
 	for _, varDef := range operation.VariableDefinitions {
-		varDef.Definition = w.Schema.Types[varDef.Type.Name()]
+		varDef.Definition = w.Schema.TypeFromAST(varDef.Type)
 
 		if varDef.DefaultValue != nil {
 			varDef.DefaultValue.ExpectedType = varDef.Type
-			varDef.DefaultValue.Definition = w.Schema.Types[varDef.Type.Name()]
+			varDef.DefaultValue.Definition = w.Schema.TypeFromAST(varDef.Type)
 		}
 	}
 
@@ -106,6 +138,7 @@ func (w *Walker) walkOperation(operation *ast.OperationDefinition) {
 		if varDef.DefaultValue != nil {
 			w.walkValue(varDef.DefaultValue)
 		}
+		w.walkDirectives(varDef.Definition, varDef.Directives, ast.LocationVariableDefinition)
 	}
 
 	w.walkSelectionSet(def, operation.SelectionSet)
@@ -156,8 +189,14 @@ func (w *Walker) walkDirectives(parentDef *ast.Definition, directives []*ast.Dir
 }
 
 func (w *Walker) walkValue(value *ast.Value) {
-	if value.Kind == ast.Variable && w.CurrentOperation != nil {
-		value.VariableDefinition = w.CurrentOperation.VariableDefinitions.ForName(value.Raw)
+	if value.Kind == ast.Variable {
+		// fragments are walked once for every operation spreading them, so the definition is looked up in the
+		// current operation each time. Standalone fragments have no variables in scope, rather than the ones of
+		// whichever operation was walked last.
+		value.VariableDefinition = nil
+		if w.CurrentOperation != nil {
+			value.VariableDefinition = w.CurrentOperation.VariableDefinitions.ForName(value.Raw)
+		}
 		if value.VariableDefinition != nil {
 			value.VariableDefinition.Used = true
 		}
@@ -169,7 +208,7 @@ func (w *Walker) walkValue(value *ast.Value) {
 				fieldDef := value.Definition.Fields.ForName(child.Name)
 				if fieldDef != nil {
 					child.Value.ExpectedType = fieldDef.Type
-					child.Value.Definition = w.Schema.Types[fieldDef.Type.Name()]
+					child.Value.Definition = w.Schema.TypeFromAST(fieldDef.Type)
 				}
 			}
 			w.walkValue(child.Value)
@@ -195,16 +234,28 @@ func (w *Walker) walkValue(value *ast.Value) {
 func (w *Walker) walkArgument(argDef *ast.ArgumentDefinition, arg *ast.Argument) {
 	if argDef != nil {
 		arg.Value.ExpectedType = argDef.Type
-		arg.Value.Definition = w.Schema.Types[argDef.Type.Name()]
+		arg.Value.Definition = w.Schema.TypeFromAST(argDef.Type)
 	}
 
 	w.walkValue(arg.Value)
 }
 
 func (w *Walker) walkSelectionSet(parentDef *ast.Definition, it ast.SelectionSet) {
+	if len(it) == 0 {
+		return
+	}
+
+	for _, v := range w.Observers.selectionSet {
+		v(w, parentDef, it)
+	}
+
 	for _, child := range it {
 		w.walkSelection(parentDef, child)
 	}
+
+	for _, v := range w.Observers.selectionSetLeave {
+		v(w, parentDef, it)
+	}
 }
 
 func (w *Walker) walkSelection(parentDef *ast.Definition, it ast.Selection) {
@@ -214,7 +265,7 @@ func (w *Walker) walkSelection(parentDef *ast.Definition, it ast.Selection) {
 		if it.Name == "__typename" {
 			def = &ast.FieldDefinition{
 				Name: "__typename",
-				Type: ast.NamedType("String", nil),
+				Type: ast.NonNullNamedType("String", nil),
 			}
 		} else if parentDef != nil {
 			def = parentDef.Fields.ForName(it.Name)
@@ -223,9 +274,15 @@ func (w *Walker) walkSelection(parentDef *ast.Definition, it ast.Selection) {
 		it.Definition = def
 		it.ObjectDefinition = parentDef
 
+		responseKey := it.Alias
+		if responseKey == "" {
+			responseKey = it.Name
+		}
+		w.path = append(w.path, ast.PathName(responseKey))
+
 		var nextParentDef *ast.Definition
 		if def != nil {
-			nextParentDef = w.Schema.Types[def.Type.Name()]
+			nextParentDef = w.Schema.TypeFromAST(def.Type)
 		}
 
 		for _, arg := range it.Arguments {
@@ -243,6 +300,7 @@ func (w *Walker) walkSelection(parentDef *ast.Definition, it ast.Selection) {
 		for _, v := range w.Observers.field {
 			v(w, it)
 		}
+		w.path = w.path[:len(w.path)-1]
 
 	case *ast.InlineFragment:
 		it.ObjectDefinition = parentDef
@@ -50,3 +50,180 @@ func TestWalkInlineFragment(t *testing.T) {
 
 	require.True(t, called)
 }
+
+func TestWalkFragmentDefinitionDirectives(t *testing.T) {
+	schema, err := LoadSchema(Prelude, &ast.Source{Input: "type Query { name: String }\n schema { query: Query }"})
+	require.Nil(t, err)
+	query, err := parser.ParseQuery(&ast.Source{Input: "{ ...Frag @include(if: true) }\n fragment Frag on Query @skip(if: true) { name }"})
+	require.Nil(t, err)
+
+	locations := map[string]ast.DirectiveLocation{}
+	observers := &Events{}
+	observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+		locations[directive.Name] = directive.Location
+	})
+
+	Walk(schema, query, observers, nil)
+
+	require.Equal(t, map[string]ast.DirectiveLocation{
+		"include": ast.LocationFragmentSpread,
+		"skip":    ast.LocationFragmentDefinition,
+	}, locations)
+}
+
+func TestWalkSelectionSets(t *testing.T) {
+	schema, err := LoadSchema(Prelude, &ast.Source{Input: `
+		type Query { user: User }
+		type User { name: String, friends: [User] }
+	`})
+	require.Nil(t, err)
+	query, err := parser.ParseQuery(&ast.Source{Input: `
+		{ user { ... on User { friends { name } } ...Friends } }
+		fragment Friends on User { friends { friends { name } } }
+	`})
+	require.Nil(t, err)
+
+	var events []string
+	depth, maxDepth := 0, 0
+	observers := &Events{}
+	observers.OnSelectionSet(func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet) {
+		events = append(events, "enter "+parentDef.Name)
+		depth++
+		if walker.CurrentOperation != nil && depth > maxDepth {
+			maxDepth = depth
+		}
+	})
+	observers.OnSelectionSetLeave(func(walker *Walker, parentDef *ast.Definition, selectionSet ast.SelectionSet) {
+		events = append(events, "leave "+parentDef.Name)
+		depth--
+	})
+
+	Walk(schema, query, observers, nil)
+
+	require.Equal(t, []string{
+		// the operation
+		"enter Query",
+		"enter User",
+		"enter User", // inline fragment
+		"enter User",
+		"leave User",
+		"leave User",
+		"enter User", // Friends, expanded at the spread
+		"enter User",
+		"enter User",
+		"leave User",
+		"leave User",
+		"leave User",
+		"leave User",
+		"leave Query",
+		// the Friends fragment definition
+		"enter User",
+		"enter User",
+		"enter User",
+		"leave User",
+		"leave User",
+		"leave User",
+	}, events)
+	require.Equal(t, 0, depth)
+	require.Equal(t, 5, maxDepth)
+}
+
+func TestWalkPath(t *testing.T) {
+	schema, err := LoadSchema(Prelude, &ast.Source{Input: `
+		type Query { user: User }
+		type User { name: String, friends: [User] }
+	`})
+	require.Nil(t, err)
+	query, err := parser.ParseQuery(&ast.Source{Input: `
+		{ me: user { ... on User { name } ...Friends } }
+		fragment Friends on User { friends { name } }
+	`})
+	require.Nil(t, err)
+
+	var paths []string
+	observers := &Events{}
+	observers.OnField(func(walker *Walker, field *ast.Field) {
+		paths = append(paths, walker.Path().String())
+	})
+
+	Walk(schema, query, observers, nil)
+
+	require.Equal(t, []string{
+		"me.name",
+		"me.friends.name",
+		"me.friends",
+		"me",
+		// the Friends fragment definition has no response path of its own
+		"",
+		"",
+	}, paths)
+}
+
+func TestWalkOperationDirectiveLocations(t *testing.T) {
+	schema, err := LoadSchema(Prelude, &ast.Source{Input: `
+		directive @op on QUERY | MUTATION | SUBSCRIPTION
+		type Query { name: String }
+		type Mutation { name: String }
+		type Subscription { name: String }
+	`})
+	require.Nil(t, err)
+	query, err := parser.ParseQuery(&ast.Source{Input: `
+		query @op { name }
+		mutation @op { name }
+		subscription @op { name }
+	`})
+	require.Nil(t, err)
+
+	var locations []ast.DirectiveLocation
+	observers := &Events{}
+	observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+		locations = append(locations, directive.Location)
+	})
+
+	Walk(schema, query, observers, nil)
+
+	require.Equal(t, []ast.DirectiveLocation{ast.LocationQuery, ast.LocationMutation, ast.LocationSubscription}, locations)
+}
+
+func TestWalkMetaFields(t *testing.T) {
+	schema, err := LoadSchema(Prelude, &ast.Source{Input: `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! }
+		union Actor = User
+		type Query { user: User node: Node actor: Actor }
+	`})
+	require.Nil(t, err)
+	query, err := parser.ParseQuery(&ast.Source{Input: `{
+		__schema { queryType { name } }
+		__type(name: "User") { name }
+		user { __typename }
+		node { __typename }
+		actor { __typename }
+	}`})
+	require.Nil(t, err)
+
+	types := map[string]string{}
+	observers := &Events{}
+	observers.OnField(func(walker *Walker, field *ast.Field) {
+		require.NotNil(t, field.Definition, field.Name)
+		if field.Name == "__typename" {
+			types[field.ObjectDefinition.Name] = field.Definition.Type.String()
+		} else if field.Name != "name" {
+			types[field.Name] = field.Definition.Type.String()
+		}
+	})
+
+	Walk(schema, query, observers, nil)
+
+	require.Equal(t, map[string]string{
+		"__schema":  "__Schema!",
+		"__type":    "__Type",
+		"queryType": "__Type!",
+		"User":      "String!",
+		"Node":      "String!",
+		"Actor":     "String!",
+		"user":      "User",
+		"node":      "Node",
+		"actor":     "Actor",
+	}, types)
+}